@@ -0,0 +1,285 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrAccountExists is returned when registering a name that is already registered
+	ErrAccountExists = errors.New("account already registered")
+	// ErrAccountNotFound is returned when a name is not registered
+	ErrAccountNotFound = errors.New("account not found")
+)
+
+// Account describes a named API key registered with an AccountManager
+type Account struct {
+	Name        string
+	Credentials CredentialProvider
+	Signer      Signer // optional, defaults to signing with the provided secret key
+
+	// RateLimiter is optional and defaults to the limiter of the manager option.
+	// 100EX limits requests per API key, so busy accounts should get their own.
+	RateLimiter RateLimiter
+}
+
+// AccountErrors maps account names to the error returned for that account
+type AccountErrors map[string]error
+
+func (e AccountErrors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, e[name]))
+	}
+
+	return "account errors: " + strings.Join(parts, "; ")
+}
+
+// AccountManager manages clients for many named accounts.
+//
+// All clients share one http.Client (and therefore one connection pool),
+// and the RateLimiter of the manager option unless the account sets its own.
+type AccountManager struct {
+	option ClientOption
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewAccountManager creates a new account manager.
+// Signer and CredentialProvider of the option are ignored; they are set per account.
+func NewAccountManager(opt ...ClientOption) *AccountManager {
+	o := defaultClientOption
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	if o.HttpClient == nil {
+		o.HttpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	for _, hook := range o.HttpClientHook {
+		hook(o.HttpClient)
+	}
+	o.HttpClientHook = nil
+	o.Signer = nil
+	o.CredentialProvider = nil
+
+	return &AccountManager{
+		option:  o,
+		clients: make(map[string]*Client),
+	}
+}
+
+// Register adds an account and returns its client
+func (m *AccountManager) Register(account Account) (*Client, error) {
+	if account.Credentials == nil {
+		return nil, fmt.Errorf("%w: account %s has no credentials", ErrEmptyCredentials, account.Name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.clients[account.Name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccountExists, account.Name)
+	}
+
+	o := m.option
+	o.CredentialProvider = account.Credentials
+	o.Signer = account.Signer
+	if account.RateLimiter != nil {
+		o.RateLimiter = account.RateLimiter
+	}
+
+	client := NewClient("", "", o)
+	m.clients[account.Name] = client
+
+	return client, nil
+}
+
+// Add registers an account with a static key pair
+func (m *AccountManager) Add(name, apiKey, secretKey string) (*Client, error) {
+	return m.Register(Account{
+		Name:        name,
+		Credentials: NewStaticCredentialProvider(apiKey, secretKey),
+	})
+}
+
+// Remove unregisters an account
+func (m *AccountManager) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.clients, name)
+}
+
+// Names returns the registered account names in sorted order
+func (m *AccountManager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Client returns the client of an account
+func (m *AccountManager) Client(name string) (*Client, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	client, ok := m.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, name)
+	}
+
+	return client, nil
+}
+
+// Exchange returns an ExchangeAPI for an account
+func (m *AccountManager) Exchange(name string) (*ExchangeAPI, error) {
+	client, err := m.Client(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.Exchange(), nil
+}
+
+// Futures returns a FuturesAPI for an account
+func (m *AccountManager) Futures(name string) (*FuturesAPI, error) {
+	client, err := m.Client(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.Futures(), nil
+}
+
+// snapshot returns a copy of the registered clients
+func (m *AccountManager) snapshot() map[string]*Client {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	clients := make(map[string]*Client, len(m.clients))
+	for name, client := range m.clients {
+		clients[name] = client
+	}
+
+	return clients
+}
+
+// FanOut calls fn concurrently for every registered account.
+// Results of successful calls are returned even if other accounts fail;
+// failures are reported as AccountErrors.
+func FanOut[T any](m *AccountManager, fn func(name string, client *Client) (T, error)) (map[string]T, error) {
+	clients := m.snapshot()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]T, len(clients))
+	errs := AccountErrors{}
+
+	for name, client := range clients {
+		wg.Add(1)
+		go func(name string, client *Client) {
+			defer wg.Done()
+
+			result, err := fn(name, client)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[name] = err
+				return
+			}
+			results[name] = result
+		}(name, client)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+
+	return results, nil
+}
+
+// GetExchangeAccounts gets the spot account of every registered account
+func (m *AccountManager) GetExchangeAccounts() (map[string]*ExchangeAccount, error) {
+	return FanOut(m, func(_ string, client *Client) (*ExchangeAccount, error) {
+		return client.Exchange().GetAccount()
+	})
+}
+
+// GetFuturesAccounts gets the futures account of every registered account
+func (m *AccountManager) GetFuturesAccounts() (map[string]*FuturesAccount, error) {
+	return FanOut(m, func(_ string, client *Client) (*FuturesAccount, error) {
+		return client.Futures().GetAccount()
+	})
+}
+
+// GetAggregateBalances sums spot balances per coin across all registered accounts.
+// Balances of accounts that failed are not included; the failures are returned as AccountErrors.
+func (m *AccountManager) GetAggregateBalances() ([]CoinBalance, error) {
+	accounts, err := m.GetExchangeAccounts()
+
+	list := make([]*ExchangeAccount, 0, len(accounts))
+	for _, account := range accounts {
+		list = append(list, account)
+	}
+
+	return SumCoinBalances(list...), err
+}
+
+// SumCoinBalances sums the coin balances of several accounts per coin, sorted by coin
+func SumCoinBalances(accounts ...*ExchangeAccount) []CoinBalance {
+	totals := make(map[string]*CoinBalance)
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+		for _, coin := range account.CoinList {
+			key := strings.ToLower(coin.Coin)
+			total, ok := totals[key]
+			if !ok {
+				total = &CoinBalance{
+					Coin:     coin.Coin,
+					Normal:   decimal.Zero,
+					Locked:   decimal.Zero,
+					BtcValue: decimal.Zero,
+					RmbValue: decimal.Zero,
+				}
+				totals[key] = total
+			}
+			total.Normal = total.Normal.Add(coin.Normal)
+			total.Locked = total.Locked.Add(coin.Locked)
+			total.BtcValue = total.BtcValue.Add(coin.BtcValue)
+			total.RmbValue = total.RmbValue.Add(coin.RmbValue)
+		}
+	}
+
+	result := make([]CoinBalance, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Coin) < strings.ToLower(result[j].Coin)
+	})
+
+	return result
+}
@@ -0,0 +1,151 @@
+package byex
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func newMockAccountManager(t *testing.T, handler http.HandlerFunc) *AccountManager {
+	t.Helper()
+
+	return NewAccountManager(ClientOption{
+		Testnet:        true,
+		HttpClientHook: []func(*http.Client){newMockServer(t, handler)},
+	})
+}
+
+func TestAccountManager_Register(t *testing.T) {
+	m := NewAccountManager()
+
+	client, err := m.Add("alpha", "key_a", "secret_a")
+	if err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+
+	if _, err := m.Add("alpha", "key_a", "secret_a"); !errors.Is(err, ErrAccountExists) {
+		t.Errorf("Expected ErrAccountExists, got %v", err)
+	}
+
+	if _, err := m.Register(Account{Name: "empty"}); !errors.Is(err, ErrEmptyCredentials) {
+		t.Errorf("Expected ErrEmptyCredentials, got %v", err)
+	}
+
+	got, err := m.Client("alpha")
+	if err != nil {
+		t.Fatalf("Client() returned error: %v", err)
+	}
+	if got != client {
+		t.Error("Client() should return the registered client")
+	}
+
+	if _, err := m.Exchange("missing"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+
+	if _, err := m.Add("beta", "key_b", "secret_b"); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+
+	names := m.Names()
+	if len(names) != 2 || names[0] != "alpha" || names[1] != "beta" {
+		t.Errorf("Unexpected names: %v", names)
+	}
+
+	m.Remove("alpha")
+	if _, err := m.Futures("alpha"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound after Remove, got %v", err)
+	}
+}
+
+func TestAccountManager_SharedResources(t *testing.T) {
+	limiter := NewRateLimiter(100, 10)
+	m := NewAccountManager(ClientOption{Testnet: true, RateLimiter: limiter})
+
+	a, _ := m.Add("alpha", "key_a", "secret_a")
+	b, _ := m.Add("beta", "key_b", "secret_b")
+
+	if a.httpClient != b.httpClient {
+		t.Error("Accounts should share one http.Client")
+	}
+	if a.limiter != b.limiter || a.limiter != limiter {
+		t.Error("Accounts should share the manager rate limiter")
+	}
+	if !a.Testnet {
+		t.Error("Accounts should inherit Testnet from the manager option")
+	}
+
+	own := NewRateLimiter(5, 1)
+	c, err := m.Register(Account{Name: "gamma", Credentials: NewStaticCredentialProvider("key_c", "secret_c"), RateLimiter: own})
+	if err != nil {
+		t.Fatalf("Register() returned error: %v", err)
+	}
+	if c.limiter != own || c.httpClient != a.httpClient {
+		t.Error("Account rate limiter should replace the shared one while keeping the shared http.Client")
+	}
+}
+
+func TestAccountManager_GetAggregateBalances(t *testing.T) {
+	m := newMockAccountManager(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("api_key") {
+		case "key_a":
+			writeMockResponse(w, `{"coin_list":[{"coin":"btc","normal":"1","locked":"0.5","btcValue":"1.5","rmbValue":"0"},{"coin":"usdt","normal":"100","locked":"0","btcValue":"0.002","rmbValue":"0"}]}`)
+		case "key_b":
+			writeMockResponse(w, `{"coin_list":[{"coin":"btc","normal":"2","locked":"0","btcValue":"2","rmbValue":"0"}]}`)
+		default:
+			w.Write([]byte(`{"code":"10002","msg":"invalid api key"}`))
+		}
+	})
+
+	m.Add("alpha", "key_a", "secret_a")
+	m.Add("beta", "key_b", "secret_b")
+
+	balances, err := m.GetAggregateBalances()
+	if err != nil {
+		t.Fatalf("GetAggregateBalances() returned error: %v", err)
+	}
+
+	if len(balances) != 2 {
+		t.Fatalf("Expected 2 coins, got %d", len(balances))
+	}
+	if balances[0].Coin != "btc" || !balances[0].Normal.Equal(decimal.NewFromInt(3)) || !balances[0].Locked.Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("Unexpected btc balance: %+v", balances[0])
+	}
+	if balances[1].Coin != "usdt" || !balances[1].Normal.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Unexpected usdt balance: %+v", balances[1])
+	}
+
+	m.Add("broken", "key_c", "secret_c")
+
+	accounts, err := m.GetExchangeAccounts()
+	var accountErrs AccountErrors
+	if !errors.As(err, &accountErrs) {
+		t.Fatalf("Expected AccountErrors, got %v", err)
+	}
+	if _, ok := accountErrs["broken"]; !ok || len(accountErrs) != 1 {
+		t.Errorf("Expected only broken account to fail, got %v", accountErrs)
+	}
+	if len(accounts) != 2 {
+		t.Errorf("Expected results for 2 healthy accounts, got %d", len(accounts))
+	}
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	limiter := NewRateLimiter(100, 5)
+
+	// The burst is free, the other 5 requests wait 10ms each
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		limiter.Wait()
+	}
+
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("Expected the requests beyond the burst to be delayed, took %s", elapsed)
+	}
+
+	unlimited := NewRateLimiter(0, 0)
+	unlimited.Wait()
+}
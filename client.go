@@ -2,11 +2,8 @@ package byex
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/md5"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -36,15 +33,44 @@ var (
 
 // Client represents the 100EX API client
 type Client struct {
-	apiKey     string
-	secretKey  string
-	httpClient *http.Client
-	Testnet    bool
+	credentials CredentialProvider
+	signer      Signer
+	limiter     RateLimiter
+	riskGuard   *RiskGuard
+	orderIDs    *ClientOrderIDGenerator
+	httpClient  *http.Client
+	strict      bool
+	Testnet     bool
 }
 
 type ClientOption struct {
 	Testnet        bool
 	HttpClientHook []func(*http.Client)
+
+	// Signer signs requests instead of the in-memory secret key.
+	// When nil, a SecretSigner built from the current secret key is used.
+	Signer Signer
+
+	// CredentialProvider is consulted on every request for the API key pair.
+	// When nil, the key pair passed to NewClient is used.
+	CredentialProvider CredentialProvider
+
+	// HttpClient is used instead of creating a new http.Client,
+	// so several clients can share one connection pool.
+	HttpClient *http.Client
+
+	// RateLimiter is waited on before every request. It may be shared by several clients.
+	RateLimiter RateLimiter
+
+	// ClientOrderIDGenerator fills in the client order ID of orders placed without one
+	ClientOrderIDGenerator *ClientOrderIDGenerator
+
+	// RiskGuard checks every order before it is sent. It may be shared by several clients.
+	RiskGuard *RiskGuard
+
+	// StrictDecoding turns off the lenient retry of response decoding, so an empty string
+	// in a numeric field fails the call. It helps to spot schema drift while debugging.
+	StrictDecoding bool
 }
 
 // NewClient creates a new client
@@ -55,16 +81,36 @@ func NewClient(apiKey, secretKey string, opt ...ClientOption) *Client {
 	}
 
 	c := &Client{
-		apiKey:     apiKey,
-		secretKey:  secretKey,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		Testnet:    o.Testnet,
+		credentials: o.CredentialProvider,
+		signer:      o.Signer,
+		limiter:     o.RateLimiter,
+		riskGuard:   o.RiskGuard,
+		httpClient:  o.HttpClient,
+		orderIDs:    o.ClientOrderIDGenerator,
+		strict:      o.StrictDecoding,
+		Testnet:     o.Testnet,
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if c.credentials == nil {
+		// A custom signer holds the secret itself, so only the API key is kept
+		if c.signer != nil {
+			secretKey = ""
+		}
+		c.credentials = NewStaticCredentialProvider(apiKey, secretKey)
 	}
 
 	for _, hook := range o.HttpClientHook {
 		hook(c.httpClient)
 	}
 
+	if c.riskGuard != nil {
+		c.riskGuard.register(c)
+	}
+
 	return c
 }
 
@@ -78,11 +124,90 @@ func (c *Client) Futures() *FuturesAPI {
 	return NewFuturesAPI(c)
 }
 
+// QueryParams are futures request params sent in the query string whatever the method.
+// Other params of POST, PUT and DELETE requests are sent as a JSON body.
+type QueryParams map[string]string
+
 // BaseResponse represents the common response structure
 type BaseResponse struct {
-	Code string      `json:"code"`
-	Msg  string      `json:"msg"`
-	Data interface{} `json:"data"`
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+
+	// strict disables the lenient retry of decodeData
+	strict bool
+}
+
+// decodeData decodes the data field of the response straight into T.
+// The API sends "" for some unset numbers, which decimal.Decimal rejects, so when
+// decoding fails it is retried with empty string values read as null.
+func decodeData[T any](resp *BaseResponse) (T, error) {
+	var result T
+	if len(resp.Data) == 0 {
+		return result, nil
+	}
+
+	err := json.Unmarshal(resp.Data, &result)
+	if err == nil {
+		return result, nil
+	}
+	if resp.strict {
+		return result, &outcomeUnknownError{err}
+	}
+
+	var lenient T
+	if json.Unmarshal(nullEmptyStrings(resp.Data), &lenient) != nil {
+		// Report the error of the original data, which points at the real problem
+		return result, &outcomeUnknownError{err}
+	}
+
+	return lenient, nil
+}
+
+// nullEmptyStrings replaces empty string values, but not empty keys, with null
+func nullEmptyStrings(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != '"' {
+			out = append(out, data[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(data) && data[end] != '"' {
+			if data[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(data) {
+			return append(out, data[i:]...)
+		}
+
+		if end == i+1 && !isJSONKey(data[end+1:]) {
+			out = append(out, "null"...)
+		} else {
+			out = append(out, data[i:end+1]...)
+		}
+		i = end
+	}
+
+	return out
+}
+
+// isJSONKey reports whether rest, the data after a string, starts with the colon of a key
+func isJSONKey(rest []byte) bool {
+	for _, c := range rest {
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
 }
 
 // Error represents an API error
@@ -95,6 +220,35 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("API Error - Code: %s, Message: %s", e.Code, e.Message)
 }
 
+// errorCodeOrderNotFound is the API error code for an order the exchange does not know
+const errorCodeOrderNotFound = "22"
+
+// isOrderNotFound reports whether err is the API error for an unknown order
+func isOrderNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == errorCodeOrderNotFound
+}
+
+// outcomeUnknownError wraps a failure after the request may have reached the exchange:
+// a transport error, or a response that could not be read. The request may have taken effect.
+type outcomeUnknownError struct {
+	err error
+}
+
+func (e *outcomeUnknownError) Error() string {
+	return e.err.Error()
+}
+
+func (e *outcomeUnknownError) Unwrap() error {
+	return e.err
+}
+
+// isOutcomeUnknown reports whether err leaves it unknown if the request took effect
+func isOutcomeUnknown(err error) bool {
+	var unknown *outcomeUnknownError
+	return errors.As(err, &unknown)
+}
+
 func (c *Client) baseUrlExchange() string {
 	if c.Testnet {
 		return _baseUrlTestnetExchange
@@ -109,10 +263,32 @@ func (c *Client) baseUrlFutures() string {
 	return _baseUrlFutures
 }
 
+// loadCredentials returns the credentials for a single request
+func (c *Client) loadCredentials() (Credentials, error) {
+	creds, err := c.credentials.Credentials()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to load credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// signerFor returns the signer to use with creds
+func (c *Client) signerFor(creds Credentials) Signer {
+	if c.signer != nil {
+		return c.signer
+	}
+	return NewSecretSigner(creds.SecretKey)
+}
+
 // generateExchangeSignature generates signature for exchange APIs
-func (c *Client) generateExchangeSignature(params map[string]string) string {
+func (c *Client) generateExchangeSignature(params map[string]string) (string, error) {
+	creds, err := c.loadCredentials()
+	if err != nil {
+		return "", err
+	}
+
 	// Add required parameters
-	params["api_key"] = c.apiKey
+	params["api_key"] = creds.APIKey
 	params["time"] = strconv.FormatInt(time.Now().UnixMilli(), 10)
 
 	// Sort keys
@@ -130,24 +306,88 @@ func (c *Client) generateExchangeSignature(params map[string]string) string {
 		}
 	}
 
-	signString := strings.Join(parts, "") + c.secretKey
-
-	// Generate MD5 hash
-	hash := md5.New()
-	hash.Write([]byte(signString))
-	return hex.EncodeToString(hash.Sum(nil))
+	return c.signerFor(creds).SignExchange(strings.Join(parts, ""))
 }
 
 // generateFuturesSignature generates signature for futures APIs
-func (c *Client) generateFuturesSignature(method, path, queryString string, timestamp int64) string {
+func (c *Client) generateFuturesSignature(method, path, queryString string, timestamp int64) (string, error) {
+	creds, err := c.loadCredentials()
+	if err != nil {
+		return "", err
+	}
+	return c.signFutures(creds, method, path, queryString, "", timestamp)
+}
+
+// signFutures signs a futures request with creds. The message is the timestamp, method,
+// path and query string followed by the JSON body, if any.
+func (c *Client) signFutures(creds Credentials, method, path, queryString, body string, timestamp int64) (string, error) {
 	message := fmt.Sprintf("%d%s%s", timestamp, method, path)
 	if queryString != "" {
 		message += "?" + queryString
 	}
+	message += body
+
+	return c.signerFor(creds).SignFutures(message)
+}
+
+// DoExchange sends a signed request to any exchange API endpoint and returns the raw data field.
+// It is meant for endpoints the SDK does not wrap yet; params is not modified.
+func (c *Client) DoExchange(method, path string, params map[string]string) (json.RawMessage, error) {
+	p := make(map[string]string, len(params))
+	for k, v := range params {
+		p[k] = v
+	}
+
+	resp, err := c.doExchangeRequest(method, path, p)
+	if err != nil {
+		return nil, err
+	}
 
-	mac := hmac.New(sha256.New, []byte(c.secretKey))
-	mac.Write([]byte(message))
-	return hex.EncodeToString(mac.Sum(nil))
+	return resp.Data, nil
+}
+
+// DoFutures sends a signed request to any futures API endpoint and returns the raw data field.
+// It is meant for endpoints the SDK does not wrap yet. GET params are sent in the query string,
+// POST, PUT and DELETE params as a JSON body; pass QueryParams to send them in the query string instead.
+func (c *Client) DoFutures(method, path string, params interface{}) (json.RawMessage, error) {
+	resp, err := c.doFuturesRequest(method, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// CallExchange sends a signed request to any exchange API endpoint and decodes the data field into T
+func CallExchange[T any](c *Client, method, path string, params map[string]string) (T, error) {
+	data, err := c.DoExchange(method, path, params)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	result, err := decodeData[T](&BaseResponse{Data: data, strict: c.strict})
+	if err != nil {
+		return result, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return result, nil
+}
+
+// CallFutures sends a signed request to any futures API endpoint and decodes the data field into T
+func CallFutures[T any](c *Client, method, path string, params interface{}) (T, error) {
+	data, err := c.DoFutures(method, path, params)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	result, err := decodeData[T](&BaseResponse{Data: data, strict: c.strict})
+	if err != nil {
+		return result, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return result, nil
 }
 
 // doExchangeRequest performs HTTP request for exchange APIs
@@ -157,14 +397,16 @@ func (c *Client) doExchangeRequest(method, path string, params map[string]string
 	}
 
 	// Generate signature
-	sign := c.generateExchangeSignature(params)
+	sign, err := c.generateExchangeSignature(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
 	params["sign"] = sign
 
 	// Build URL
 	reqURL := c.baseUrlExchange() + path
 
 	var req *http.Request
-	var err error
 
 	if method == "GET" {
 		// For GET requests, add params to query string
@@ -204,50 +446,71 @@ func (c *Client) doFuturesRequest(method, path string, params interface{}) (*Bas
 	// Build URL
 	reqURL := c.baseUrlFutures() + path
 
-	var req *http.Request
-	var err error
 	var queryString string
-
-	if method == "GET" && params != nil {
-		// For GET requests with params, add to query string
-		if paramMap, ok := params.(map[string]string); ok {
-			u, _ := url.Parse(reqURL)
-			q := u.Query()
-			for k, v := range paramMap {
-				if v != "" {
-					q.Set(k, v)
-				}
+	var body []byte
+
+	switch query, isQuery := params.(QueryParams); {
+	case params == nil:
+	case isQuery || method == http.MethodGet:
+		// GET requests, and any request whose endpoint takes QueryParams, send params in the query string
+		if !isQuery {
+			paramMap, ok := params.(map[string]string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported params type %T for GET request", params)
 			}
-			if len(q) > 0 {
-				queryString = q.Encode()
-				u.RawQuery = queryString
-				reqURL = u.String()
+			query = paramMap
+		}
+
+		u, _ := url.Parse(reqURL)
+		q := u.Query()
+		for k, v := range query {
+			if v != "" {
+				q.Set(k, v)
 			}
 		}
-		req, err = http.NewRequest(method, reqURL, nil)
-	} else if method == "POST" && params != nil {
-		// For POST requests, send as JSON
-		jsonData, jsonErr := json.Marshal(params)
-		if jsonErr != nil {
-			return nil, fmt.Errorf("failed to marshal params: %w", jsonErr)
+		if len(q) > 0 {
+			queryString = q.Encode()
+			u.RawQuery = queryString
+			reqURL = u.String()
 		}
-		req, err = http.NewRequest(method, reqURL, bytes.NewBuffer(jsonData))
-		if err == nil {
-			req.Header.Set("Content-Type", "application/json")
+	case method == http.MethodPost || method == http.MethodPut || method == http.MethodDelete:
+		// Other requests send params as a JSON body, which is covered by the signature
+		jsonData, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
 		}
-	} else {
-		req, err = http.NewRequest(method, reqURL, nil)
+		body = jsonData
+	default:
+		// Refuse rather than silently sending the request without its params
+		return nil, fmt.Errorf("params are not supported for %s requests", method)
 	}
 
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, reqURL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Load credentials once so the key and signature always match
+	creds, err := c.loadCredentials()
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate signature
-	sign := c.generateFuturesSignature(method, path, queryString, timestamp)
+	sign, err := c.signFutures(creds, method, path, queryString, string(body), timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
 
 	// Set required headers
-	req.Header.Set("X-CH-APIKEY", c.apiKey)
+	req.Header.Set("X-CH-APIKEY", creds.APIKey)
 	req.Header.Set("X-CH-TS", strconv.FormatInt(timestamp, 10))
 	req.Header.Set("X-CH-SIGN", sign)
 
@@ -256,21 +519,26 @@ func (c *Client) doFuturesRequest(method, path string, params interface{}) (*Bas
 
 // executeRequest executes the HTTP request and parses response
 func (c *Client) executeRequest(req *http.Request) (*BaseResponse, error) {
+	if c.limiter != nil {
+		c.limiter.Wait()
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, &outcomeUnknownError{fmt.Errorf("request failed: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, &outcomeUnknownError{fmt.Errorf("failed to read response: %w", err)}
 	}
 
 	var baseResp BaseResponse
 	if err := json.Unmarshal(body, &baseResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, &outcomeUnknownError{fmt.Errorf("failed to parse response: %w", err)}
 	}
+	baseResp.strict = c.strict
 
 	// Check for API errors
 	if baseResp.Code != "0" && baseResp.Code != "" {
@@ -1,9 +1,20 @@
 package byex
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -76,12 +87,17 @@ func TestNewClient(t *testing.T) {
 				return
 			}
 
-			if client.apiKey != tt.apiKey {
-				t.Errorf("Expected apiKey %s, got %s", tt.apiKey, client.apiKey)
+			creds, err := client.loadCredentials()
+			if err != nil {
+				t.Fatalf("loadCredentials() returned error: %v", err)
+			}
+
+			if creds.APIKey != tt.apiKey {
+				t.Errorf("Expected apiKey %s, got %s", tt.apiKey, creds.APIKey)
 			}
 
-			if client.secretKey != tt.secretKey {
-				t.Errorf("Expected secretKey %s, got %s", tt.secretKey, client.secretKey)
+			if creds.SecretKey != tt.secretKey {
+				t.Errorf("Expected secretKey %s, got %s", tt.secretKey, creds.SecretKey)
 			}
 
 			if len(tt.options) > 0 && client.Testnet != tt.options[0].Testnet {
@@ -222,7 +238,10 @@ func TestClient_generateExchangeSignature(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			signature := client.generateExchangeSignature(tt.params)
+			signature, err := client.generateExchangeSignature(tt.params)
+			if err != nil {
+				t.Fatalf("generateExchangeSignature() returned error: %v", err)
+			}
 
 			if signature == "" {
 				t.Error("generateExchangeSignature() returned empty signature")
@@ -233,7 +252,7 @@ func TestClient_generateExchangeSignature(t *testing.T) {
 			}
 
 			// Verify signature is consistent
-			signature2 := client.generateExchangeSignature(tt.params)
+			signature2, _ := client.generateExchangeSignature(tt.params)
 			// Note: signatures will be different due to timestamp, this is expected
 			if signature == signature2 {
 				t.Log("Note: Signatures are the same, this could happen if timestamps are identical")
@@ -284,7 +303,10 @@ func TestClient_generateFuturesSignature(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			signature := client.generateFuturesSignature(tt.method, tt.path, tt.queryString, tt.timestamp)
+			signature, err := client.generateFuturesSignature(tt.method, tt.path, tt.queryString, tt.timestamp)
+			if err != nil {
+				t.Fatalf("generateFuturesSignature() returned error: %v", err)
+			}
 
 			if signature == "" {
 				t.Error("generateFuturesSignature() returned empty signature")
@@ -295,7 +317,7 @@ func TestClient_generateFuturesSignature(t *testing.T) {
 			}
 
 			// Verify signature is consistent with same input
-			signature2 := client.generateFuturesSignature(tt.method, tt.path, tt.queryString, tt.timestamp)
+			signature2, _ := client.generateFuturesSignature(tt.method, tt.path, tt.queryString, tt.timestamp)
 			if signature != signature2 {
 				t.Error("generateFuturesSignature() should return consistent signatures for same input")
 			}
@@ -373,3 +395,377 @@ func TestClientOption(t *testing.T) {
 // Note: doExchangeRequest and doFuturesRequest are harder to test without actual HTTP mocking
 // These would require more complex test setup with HTTP test servers
 // For now, these tests focus on the signature generation and basic client functionality
+
+// redirectTransport sends every request to the test server regardless of the requested host
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newMockServer starts a test server and returns a hook redirecting an http.Client to it
+func newMockServer(t testing.TB, handler http.HandlerFunc) func(*http.Client) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	return func(client *http.Client) {
+		client.Transport = redirectTransport{target: target}
+	}
+}
+
+// newMockClient creates a client whose exchange and futures requests are served by handler
+func newMockClient(t testing.TB, handler http.HandlerFunc, opt ...ClientOption) *Client {
+	t.Helper()
+
+	o := ClientOption{Testnet: true}
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+	o.HttpClientHook = append(o.HttpClientHook, newMockServer(t, handler))
+
+	return NewClient(testApiKey, testSecretKey, o)
+}
+
+// writeMockResponse writes a successful API response with data as the data field
+func writeMockResponse(w http.ResponseWriter, data string) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"code":"0","msg":"success","data":%s}`, data)
+}
+
+func TestDecodeData(t *testing.T) {
+	resp := &BaseResponse{Data: json.RawMessage(`{"asks":[["48050","1.5"]],"bids":[["47950","2"]]}`)}
+
+	depth, err := decodeData[ExchangeDepth](resp)
+	if err != nil {
+		t.Fatalf("decodeData() returned error: %v", err)
+	}
+	if len(depth.Asks) != 1 || !depth.Asks[0][0].Equal(decimal.NewFromInt(48050)) {
+		t.Errorf("Unexpected asks: %v", depth.Asks)
+	}
+
+	// Missing and null data decode to the zero value
+	for _, data := range []json.RawMessage{nil, json.RawMessage(`null`)} {
+		orders, err := decodeData[[]FuturesOrder](&BaseResponse{Data: data})
+		if err != nil {
+			t.Errorf("decodeData(%q) returned error: %v", data, err)
+		}
+		if orders != nil {
+			t.Errorf("decodeData(%q) expected nil slice, got %v", data, orders)
+		}
+	}
+
+	if _, err := decodeData[ExchangeDepth](&BaseResponse{Data: json.RawMessage(`[1,2]`)}); err == nil {
+		t.Error("decodeData() expected error for mismatched data")
+	}
+}
+
+func TestDecodeData_EmptyNumbers(t *testing.T) {
+	data := json.RawMessage(`[{"id":"1","price":"","amount":2,"avg_price":null,"fee":"0.1","source":"","":""}]`)
+
+	orders, err := decodeData[[]ExchangeOrder](&BaseResponse{Data: data})
+	if err != nil {
+		t.Fatalf("decodeData() returned error: %v", err)
+	}
+	if !orders[0].Price.IsZero() || !orders[0].Amount.Equal(decimal.NewFromInt(2)) || orders[0].Fee.String() != "0.1" {
+		t.Errorf("Unexpected order: %+v", orders[0])
+	}
+
+	if _, err := decodeData[[]ExchangeOrder](&BaseResponse{Data: data, strict: true}); err == nil {
+		t.Error("decodeData() expected error for an empty price in strict mode")
+	}
+
+	if got := string(nullEmptyStrings([]byte(`{"a":"","b" : "x\"","":"", "c":[""]}`))); got != `{"a":null,"b" : "x\"","":null, "c":[null]}` {
+		t.Errorf("Unexpected nullEmptyStrings output %s", got)
+	}
+}
+
+func TestClient_StrictDecoding(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		writeMockResponse(w, `{"resultList":[{"id":"1","symbol":"btcusdt","price":"","volume":"1"}],"count":1}`)
+	}
+
+	client := newMockClient(t, handler)
+	if _, err := client.Exchange().GetCurrentOrders("btcusdt", 10, 1); err != nil {
+		t.Errorf("Expected empty price to decode, got %v", err)
+	}
+
+	client = newMockClient(t, handler, ClientOption{Testnet: true, StrictDecoding: true})
+	if _, err := client.Exchange().GetCurrentOrders("btcusdt", 10, 1); err == nil {
+		t.Error("Expected an error for an empty price with StrictDecoding")
+	}
+}
+
+// mockDepthData builds a depth payload with n levels on each side
+func mockDepthData(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"code":"0","msg":"success","data":{"asks":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `["%d.5","%d.25"]`, 48000+i, i+1)
+	}
+	buf.WriteString(`],"bids":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `["%d.5","%d.25"]`, 47000-i, i+1)
+	}
+	buf.WriteString(`]}}`)
+	return buf.Bytes()
+}
+
+// mockAllDepthData builds an all futures depth payload with the given number of contracts
+func mockAllDepthData(contracts, levels int) []byte {
+	depth := mockDepthData(levels)
+	data := depth[bytes.Index(depth, []byte(`"data":`))+len(`"data":`) : len(depth)-1]
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"code":"0","msg":"success","data":{`)
+	for i := 0; i < contracts; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `"E-COIN%d-USDT":%s`, i, data)
+	}
+	buf.WriteString(`}}`)
+	return buf.Bytes()
+}
+
+// decodeRoundTrip reproduces the former decoding path: data as interface{}, marshalled and unmarshalled again
+func decodeRoundTrip(body []byte, v interface{}) error {
+	var resp struct {
+		Code string      `json:"code"`
+		Msg  string      `json:"msg"`
+		Data interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	dataBytes, err := json.Marshal(resp.Data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(dataBytes, v)
+}
+
+// decodeRaw follows the current decoding path used by executeRequest and decodeData
+func decodeRaw[T any](body []byte) (T, error) {
+	var resp BaseResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		var zero T
+		return zero, err
+	}
+	return decodeData[T](&resp)
+}
+
+func BenchmarkDecodeDepth_RoundTrip(b *testing.B) {
+	body := mockDepthData(100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var depth ExchangeDepth
+		if err := decodeRoundTrip(body, &depth); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeDepth_RawMessage(b *testing.B) {
+	body := mockDepthData(100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeRaw[ExchangeDepth](body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeAllFuturesDepth_RoundTrip(b *testing.B) {
+	body := mockAllDepthData(20, 50)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var depth map[string]ExchangeDepth
+		if err := decodeRoundTrip(body, &depth); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeAllFuturesDepth_RawMessage(b *testing.B) {
+	body := mockAllDepthData(20, 50)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeRaw[map[string]ExchangeDepth](body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestClient_DoExchange(t *testing.T) {
+	var gotPath, gotMethod string
+	var gotQuery url.Values
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotMethod, gotQuery = r.URL.Path, r.Method, r.URL.Query()
+		writeMockResponse(w, `{"symbol":"BTCUSDT","last":"48000"}`)
+	})
+
+	params := map[string]string{"symbol": "BTCUSDT"}
+	data, err := client.DoExchange("GET", "/open/api/new_endpoint", params)
+	if err != nil {
+		t.Fatalf("DoExchange() returned error: %v", err)
+	}
+
+	if gotMethod != "GET" || gotPath != "/open/api/new_endpoint" {
+		t.Errorf("Unexpected request %s %s", gotMethod, gotPath)
+	}
+	for _, key := range []string{"symbol", "api_key", "time", "sign"} {
+		if gotQuery.Get(key) == "" {
+			t.Errorf("Expected %s in query, got %v", key, gotQuery)
+		}
+	}
+	if len(params) != 1 {
+		t.Errorf("DoExchange() should not modify params, got %v", params)
+	}
+	if string(data) != `{"symbol":"BTCUSDT","last":"48000"}` {
+		t.Errorf("Unexpected raw data: %s", data)
+	}
+
+	ticker, err := CallExchange[ExchangeTicker](client, "GET", "/open/api/new_endpoint", params)
+	if err != nil {
+		t.Fatalf("CallExchange() returned error: %v", err)
+	}
+	if ticker.Symbol != "BTCUSDT" || !ticker.Last.Equal(decimal.NewFromInt(48000)) {
+		t.Errorf("Unexpected ticker: %+v", ticker)
+	}
+}
+
+func TestClient_DoFutures(t *testing.T) {
+	var gotBody map[string]interface{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-CH-APIKEY") != testApiKey || r.Header.Get("X-CH-SIGN") == "" || r.Header.Get("X-CH-TS") == "" {
+			t.Errorf("Missing futures auth headers: %v", r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		writeMockResponse(w, `[{"orderId":"1"},{"orderId":"2"}]`)
+	})
+
+	orders, err := CallFutures[[]OrderResponse](client, "POST", "/fapi/v1/new_endpoint", map[string]interface{}{"futuresName": "E-BTC-USDT"})
+	if err != nil {
+		t.Fatalf("CallFutures() returned error: %v", err)
+	}
+	if gotBody["futuresName"] != "E-BTC-USDT" {
+		t.Errorf("Unexpected body: %v", gotBody)
+	}
+	if len(orders) != 2 || orders[1].OrderID != "2" {
+		t.Errorf("Unexpected orders: %+v", orders)
+	}
+
+	requests := 0
+	client = newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeMockResponse(w, `null`)
+	})
+	if _, err := client.DoFutures("PATCH", "/fapi/v1/new_endpoint", map[string]string{"orderId": "1"}); err == nil {
+		t.Error("DoFutures() should refuse params it cannot send")
+	}
+	if _, err := client.DoFutures("GET", "/fapi/v1/new_endpoint", struct{ ID string }{"1"}); err == nil {
+		t.Error("DoFutures() should refuse a GET params type it cannot encode")
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests to be sent, got %d", requests)
+	}
+}
+
+func TestClient_DoFuturesBody(t *testing.T) {
+	type request struct {
+		method, query, contentType string
+		body                       []byte
+	}
+	var got request
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = request{r.Method, r.URL.RawQuery, r.Header.Get("Content-Type"), body}
+		checkFuturesSignature(t, r, body)
+		writeMockResponse(w, `null`)
+	})
+
+	tests := []struct {
+		method string
+		params interface{}
+		want   request
+	}{
+		{"POST", map[string]string{"futuresName": "E-BTC-USDT"}, request{"POST", "", "application/json", []byte(`{"futuresName":"E-BTC-USDT"}`)}},
+		{"PUT", map[string]interface{}{"orderId": "1", "price": "48000"}, request{"PUT", "", "application/json", []byte(`{"orderId":"1","price":"48000"}`)}},
+		{"DELETE", map[string]interface{}{"orderIdList": []string{"1", "2"}}, request{"DELETE", "", "application/json", []byte(`{"orderIdList":["1","2"]}`)}},
+		{"DELETE", QueryParams{"orderId": "1", "symbol": ""}, request{"DELETE", "orderId=1", "", nil}},
+		{"POST", QueryParams{"futuresName": "E-BTC-USDT"}, request{"POST", "futuresName=E-BTC-USDT", "", nil}},
+		{"DELETE", nil, request{"DELETE", "", "", nil}},
+	}
+
+	for _, tt := range tests {
+		if _, err := client.DoFutures(tt.method, "/fapi/v1/new_endpoint", tt.params); err != nil {
+			t.Fatalf("DoFutures(%s) returned error: %v", tt.method, err)
+		}
+		if got.method != tt.want.method || got.query != tt.want.query || got.contentType != tt.want.contentType || !bytes.Equal(got.body, tt.want.body) {
+			t.Errorf("DoFutures(%s, %v): expected %+v, got %+v", tt.method, tt.params, tt.want, got)
+		}
+	}
+}
+
+// checkFuturesSignature verifies the X-CH-SIGN header of r against its query string and body
+func checkFuturesSignature(t *testing.T, r *http.Request, body []byte) {
+	t.Helper()
+
+	message := r.Header.Get("X-CH-TS") + r.Method + r.URL.Path
+	if r.URL.RawQuery != "" {
+		message += "?" + r.URL.RawQuery
+	}
+	mac := hmac.New(sha256.New, []byte(testSecretKey))
+	mac.Write([]byte(message))
+	mac.Write(body)
+	if want := hex.EncodeToString(mac.Sum(nil)); r.Header.Get("X-CH-SIGN") != want {
+		t.Errorf("Expected signature %s over %q, got %s", want, message+string(body), r.Header.Get("X-CH-SIGN"))
+	}
+}
+
+func TestClient_DoRequestAPIError(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"10004","msg":"symbol not found"}`))
+	})
+
+	_, err := client.DoFutures("GET", "/fapi/v1/new_endpoint", nil)
+	apiErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Expected *Error, got %v", err)
+	}
+	if apiErr.Code != "10004" {
+		t.Errorf("Expected code 10004, got %s", apiErr.Code)
+	}
+
+	if _, err := CallExchange[ExchangeTicker](client, "GET", "/open/api/new_endpoint", nil); err == nil {
+		t.Error("CallExchange() expected API error")
+	}
+}
@@ -0,0 +1,79 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MaxClientOrderIDLength is the longest client order ID accepted by the exchange
+const MaxClientOrderIDLength = 32
+
+// ErrClientOrderIDTooLong is returned when a client order ID exceeds MaxClientOrderIDLength
+var ErrClientOrderIDTooLong = errors.New("client order id too long")
+
+// ClientOrderIDGenerator generates unique client order IDs of the form
+//
+//	<Prefix><Strategy><session><counter>
+//
+// session is the generator creation time in base 36, so IDs stay unique across
+// restarts, and counter is a monotonic base 36 sequence. Only letters and digits
+// are used so the IDs are accepted by both spot and futures APIs.
+//
+// Keep Prefix and Strategy short: the session and counter take 12 characters,
+// and the whole ID must fit in MaxClientOrderIDLength.
+type ClientOrderIDGenerator struct {
+	Prefix   string
+	Strategy string
+
+	once    sync.Once
+	session string
+	counter uint64
+}
+
+// NewClientOrderIDGenerator creates a generator with a prefix and a strategy tag
+func NewClientOrderIDGenerator(prefix, strategy string) *ClientOrderIDGenerator {
+	return &ClientOrderIDGenerator{
+		Prefix:   prefix,
+		Strategy: strategy,
+	}
+}
+
+// Next returns a new client order ID. It is safe for concurrent use.
+func (g *ClientOrderIDGenerator) Next() string {
+	// The session is set on first use so generators built as struct literals stay unique across restarts
+	g.once.Do(func() {
+		g.session = strconv.FormatInt(time.Now().UnixMilli(), 36)
+	})
+
+	n := atomic.AddUint64(&g.counter, 1)
+	seq := strconv.FormatUint(n, 36)
+	if len(seq) < 4 {
+		seq = strings.Repeat("0", 4-len(seq)) + seq
+	}
+
+	return g.Prefix + g.Strategy + g.session + seq
+}
+
+// nextClientOrderID returns id if set, otherwise a generated ID when the client has a generator
+func (c *Client) nextClientOrderID(id string) (string, error) {
+	if id == "" && c.orderIDs != nil {
+		id = c.orderIDs.Next()
+	}
+	if err := validateClientOrderID(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// validateClientOrderID checks id against the exchange length limit before it is sent
+func validateClientOrderID(id string) error {
+	if len(id) > MaxClientOrderIDLength {
+		return fmt.Errorf("%w: %q has %d characters, the limit is %d", ErrClientOrderIDTooLong, id, len(id), MaxClientOrderIDLength)
+	}
+	return nil
+}
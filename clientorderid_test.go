@@ -0,0 +1,192 @@
+package byex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestClientOrderIDGenerator_Next(t *testing.T) {
+	g := NewClientOrderIDGenerator("bx", "mm")
+
+	first := g.Next()
+	second := g.Next()
+
+	if !strings.HasPrefix(first, "bxmm") {
+		t.Errorf("Expected prefix and strategy tag, got %s", first)
+	}
+	if first == second {
+		t.Error("Generated IDs should be unique")
+	}
+	if first >= second {
+		t.Errorf("Generated IDs should increase: %s >= %s", first, second)
+	}
+	for _, r := range first {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			t.Errorf("Unexpected character %q in %s", r, first)
+		}
+	}
+}
+
+func TestClientOrderIDGenerator_Literal(t *testing.T) {
+	g := &ClientOrderIDGenerator{Prefix: "x"}
+
+	id := g.Next()
+	if len(id) <= len("x")+4 {
+		t.Errorf("Generator built as a literal should include a session, got %s", id)
+	}
+	if len(id) > MaxClientOrderIDLength {
+		t.Errorf("Generated ID %s exceeds %d characters", id, MaxClientOrderIDLength)
+	}
+}
+
+func TestClient_ClientOrderIDTooLong(t *testing.T) {
+	requests := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeMockResponse(w, `{"orderId":"1"}`)
+	}, ClientOption{ClientOrderIDGenerator: NewClientOrderIDGenerator(strings.Repeat("p", 16), strings.Repeat("s", 8))})
+
+	_, err := client.Exchange().CreateOrder(CreateOrderRequest{Symbol: "BTCUSDT"})
+	if !errors.Is(err, ErrClientOrderIDTooLong) {
+		t.Errorf("Expected ErrClientOrderIDTooLong for a generated ID, got %v", err)
+	}
+
+	_, err = client.Futures().CreateOrder(FuturesCreateOrderRequest{ClientOrderID: strings.Repeat("c", MaxClientOrderIDLength+1)})
+	if !errors.Is(err, ErrClientOrderIDTooLong) {
+		t.Errorf("Expected ErrClientOrderIDTooLong for an explicit ID, got %v", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("Expected no requests to be sent, got %d", requests)
+	}
+}
+
+func TestClientOrderIDGenerator_Concurrent(t *testing.T) {
+	g := NewClientOrderIDGenerator("", "")
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[string]bool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := g.Next()
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("Duplicate ID %s", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClient_ClientOrderIDAutoApplied(t *testing.T) {
+	var spotIDs, futuresIDs []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/create_order":
+			r.ParseForm()
+			spotIDs = append(spotIDs, r.PostForm.Get("client_order_id"))
+			writeMockResponse(w, `{"orderId":"1"}`)
+		case "/fapi/v1/trade/order":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			id, _ := body["clientOrderId"].(string)
+			futuresIDs = append(futuresIDs, id)
+			writeMockResponse(w, `{"orderId":"2"}`)
+		}
+	}, ClientOption{ClientOrderIDGenerator: NewClientOrderIDGenerator("bx", "")})
+
+	resp, err := client.Exchange().CreateOrder(CreateOrderRequest{
+		Symbol: "BTCUSDT",
+		Side:   OrderSideBuy,
+		Type:   OrderTypeLimit,
+		Amount: decimal.NewFromInt(1),
+		Price:  decimal.NewFromInt(45000),
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder() returned error: %v", err)
+	}
+	if !strings.HasPrefix(spotIDs[0], "bx") || resp.ClientOrderID != spotIDs[0] {
+		t.Errorf("Expected generated client order ID %q to be sent and returned, got %q", spotIDs[0], resp.ClientOrderID)
+	}
+
+	resp, err = client.Exchange().CreateOrder(CreateOrderRequest{Symbol: "BTCUSDT", ClientOrderID: "mine"})
+	if err != nil {
+		t.Fatalf("CreateOrder() returned error: %v", err)
+	}
+	if spotIDs[1] != "mine" || resp.ClientOrderID != "mine" {
+		t.Errorf("Explicit client order ID should be kept, got %q", spotIDs[1])
+	}
+
+	resp, err = client.Futures().CreateOrder(FuturesCreateOrderRequest{FuturesName: "E-BTC-USDT", Volume: decimal.NewFromInt(1)})
+	if err != nil {
+		t.Fatalf("CreateOrder() returned error: %v", err)
+	}
+	if !strings.HasPrefix(futuresIDs[0], "bx") || resp.ClientOrderID != futuresIDs[0] {
+		t.Errorf("Expected generated futures client order ID, got %q", futuresIDs[0])
+	}
+}
+
+func TestClient_ClientOrderIDLookup(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/open/api/order_info":
+			if r.URL.Query().Get("client_order_id") != "abc" {
+				t.Errorf("Expected client_order_id abc, got %v", r.URL.Query())
+			}
+			writeMockResponse(w, `{"id":"1","client_order_id":"abc","status":"NEW"}`)
+		case r.URL.Path == "/open/api/cancel_order":
+			r.ParseForm()
+			if r.PostForm.Get("client_order_id") != "abc" || r.PostForm.Get("order_id") != "" {
+				t.Errorf("Unexpected cancel form: %v", r.PostForm)
+			}
+			writeMockResponse(w, `null`)
+		case r.URL.Path == "/fapi/v1/trade/order":
+			if r.URL.Query().Get("clientOrderId") != "def" {
+				t.Errorf("Expected clientOrderId def, got %v", r.URL.Query())
+			}
+			writeMockResponse(w, `{"orderId":"2","clientOrderId":"def","status":"NEW"}`)
+		case r.URL.Path == "/fapi/v1/trade/cancel":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["clientOrderId"] != "def" {
+				t.Errorf("Unexpected cancel body: %v", body)
+			}
+			writeMockResponse(w, `null`)
+		}
+	})
+
+	order, err := client.Exchange().GetOrderInfoByClientOrderID("BTCUSDT", "abc")
+	if err != nil {
+		t.Fatalf("GetOrderInfoByClientOrderID() returned error: %v", err)
+	}
+	if order.ID != "1" || order.ClientOrderID != "abc" {
+		t.Errorf("Unexpected order: %+v", order)
+	}
+	if err := client.Exchange().CancelOrderByClientOrderID("BTCUSDT", "abc"); err != nil {
+		t.Errorf("CancelOrderByClientOrderID() returned error: %v", err)
+	}
+
+	futuresOrder, err := client.Futures().GetOrderInfoByClientOrderID("E-BTC-USDT", "def")
+	if err != nil {
+		t.Fatalf("GetOrderInfoByClientOrderID() returned error: %v", err)
+	}
+	if futuresOrder.OrderID != "2" || futuresOrder.ClientOrderID != "def" {
+		t.Errorf("Unexpected futures order: %+v", futuresOrder)
+	}
+	if err := client.Futures().CancelOrderByClientOrderID("E-BTC-USDT", "def"); err != nil {
+		t.Errorf("CancelOrderByClientOrderID() returned error: %v", err)
+	}
+}
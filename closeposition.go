@@ -0,0 +1,150 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrNoPosition is returned by ClosePosition when there is nothing to close
+	ErrNoPosition = errors.New("no open position")
+	// ErrInvalidCloseFraction is returned for a fraction outside (0, 1]
+	ErrInvalidCloseFraction = errors.New("close fraction must be greater than 0 and at most 1")
+)
+
+// ClosePositionOptions configures the closing orders built by ClosePosition
+type ClosePositionOptions struct {
+	// Price places limit closing orders at this price. Zero closes at market.
+	Price decimal.Decimal
+	// StepSize rounds partial closing volumes down to a multiple of it. Zero rounds
+	// down to whole contracts. A fraction of 1 always closes the whole position.
+	StepSize decimal.Decimal
+}
+
+// ClosePosition closes fraction (0, 1] of every open position of futuresName.
+// Long and short positions are closed with an order on the opposite side,
+// and the placed orders are returned in position order.
+func (f *FuturesAPI) ClosePosition(futuresName string, fraction decimal.Decimal, opt ...ClosePositionOptions) ([]OrderResponse, error) {
+	if !fraction.IsPositive() || fraction.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCloseFraction, fraction)
+	}
+
+	var o ClosePositionOptions
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	positions, err := f.GetPositions(futuresName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var reqs []FuturesCreateOrderRequest
+	for _, position := range positions {
+		if req, ok := closingOrder(futuresName, position, fraction, o); ok {
+			reqs = append(reqs, req)
+		}
+	}
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoPosition, futuresName)
+	}
+
+	return f.placeClosingOrders(reqs)
+}
+
+// CloseAllPositions closes every open position of the account at market.
+// It keeps going when an order fails, returning the placed orders and the joined errors.
+func (f *FuturesAPI) CloseAllPositions() ([]OrderResponse, error) {
+	positions, err := f.GetAllPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var reqs []FuturesCreateOrderRequest
+	for _, position := range positions {
+		if req, ok := closingOrder(position.Symbol, position, decimal.NewFromInt(1), ClosePositionOptions{}); ok {
+			reqs = append(reqs, req)
+		}
+	}
+
+	return f.placeClosingOrders(reqs)
+}
+
+// placeClosingOrders places reqs one by one, collecting every failure
+func (f *FuturesAPI) placeClosingOrders(reqs []FuturesCreateOrderRequest) ([]OrderResponse, error) {
+	var (
+		result []OrderResponse
+		errs   []error
+	)
+	for _, req := range reqs {
+		resp, err := f.CreateOrder(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s %s position: %w", req.FuturesName, req.Side, err))
+			continue
+		}
+		result = append(result, *resp)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// closingOrder builds the order closing fraction of position. It reports false
+// when the position is empty or the rounded volume is zero.
+func closingOrder(futuresName string, position FuturesPosition, fraction decimal.Decimal, o ClosePositionOptions) (FuturesCreateOrderRequest, bool) {
+	volume := position.PositionAmt.Abs()
+	if fraction.LessThan(decimal.NewFromInt(1)) {
+		step := o.StepSize
+		if !step.IsPositive() {
+			step = decimal.NewFromInt(1)
+		}
+		volume = volume.Mul(fraction).Div(step).Floor().Mul(step)
+	}
+	if !volume.IsPositive() {
+		return FuturesCreateOrderRequest{}, false
+	}
+
+	// Closing a long sells, closing a short buys
+	var side OrderSide = OrderSideSell
+	if !isLongPosition(position) {
+		side = OrderSideBuy
+	}
+
+	req := FuturesCreateOrderRequest{
+		FuturesName:  futuresName,
+		Type:         OrderTypeMarket,
+		Side:         side,
+		Open:         FuturesTradeTypeClose,
+		PositionType: positionTypeOf(position),
+		Volume:       volume,
+	}
+	if o.Price.IsPositive() {
+		req.Type = OrderTypeLimit
+		req.Price = o.Price
+	}
+
+	return req, true
+}
+
+// isLongPosition reports whether position is long, using the position side
+// and falling back to the sign of the amount in one-way mode
+func isLongPosition(position FuturesPosition) bool {
+	switch strings.ToUpper(position.PositionSide) {
+	case "LONG", OrderSideBuy:
+		return true
+	case "SHORT", OrderSideSell:
+		return false
+	default:
+		return !position.PositionAmt.IsNegative()
+	}
+}
+
+// positionTypeOf maps the margin type of a position onto its position type code
+func positionTypeOf(position FuturesPosition) PositionType {
+	if p, _ := ParsePositionType(position.MarginType); p == FuturesPositionTypeIsolated {
+		return FuturesPositionTypeIsolated
+	}
+	return FuturesPositionTypeCross
+}
@@ -0,0 +1,139 @@
+package byex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+const testPositions = `[
+	{"symbol":"E-BTC-USDT","positionSide":"LONG","positionAmt":"10","marginType":"ISOLATED"},
+	{"symbol":"E-BTC-USDT","positionSide":"SHORT","positionAmt":"-5","marginType":"CROSSED"},
+	{"symbol":"E-ETH-USDT","positionSide":"BOTH","positionAmt":"0","marginType":"CROSSED"}
+]`
+
+func TestFuturesAPI_ClosePosition(t *testing.T) {
+	var orders []map[string]interface{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/position/positions":
+			if r.URL.Query().Get("futuresName") != "E-BTC-USDT" {
+				t.Errorf("Unexpected query %v", r.URL.Query())
+			}
+			writeMockResponse(w, testPositions)
+		case "/fapi/v1/trade/order":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			orders = append(orders, body)
+			writeMockResponse(w, fmt.Sprintf(`{"orderId":"%d"}`, len(orders)))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	resp, err := client.Futures().ClosePosition("E-BTC-USDT", decimal.RequireFromString("0.5"), ClosePositionOptions{
+		Price:    decimal.NewFromInt(45000),
+		StepSize: decimal.NewFromInt(1),
+	})
+	if err != nil {
+		t.Fatalf("ClosePosition() returned error: %v", err)
+	}
+	if len(resp) != 2 {
+		t.Fatalf("Expected 2 closing orders, got %d", len(resp))
+	}
+
+	expected := []map[string]interface{}{
+		{"side": OrderSideSell, "volume": "5", "positionType": FuturesPositionTypeIsolated},
+		{"side": OrderSideBuy, "volume": "2", "positionType": FuturesPositionTypeCross},
+	}
+	for i, want := range expected {
+		for k, v := range want {
+			if orders[i][k] != v {
+				t.Errorf("Order %d: expected %s %v, got %v", i, k, v, orders[i][k])
+			}
+		}
+		if orders[i]["open"] != FuturesTradeTypeClose || orders[i]["type"] != OrderTypeLimit || orders[i]["price"] != "45000" {
+			t.Errorf("Order %d: unexpected closing order %v", i, orders[i])
+		}
+	}
+
+	if _, err := client.Futures().ClosePosition("E-BTC-USDT", decimal.NewFromInt(2)); !errors.Is(err, ErrInvalidCloseFraction) {
+		t.Errorf("Expected ErrInvalidCloseFraction, got %v", err)
+	}
+}
+
+func TestFuturesAPI_ClosePositionWholeContracts(t *testing.T) {
+	var volumes []interface{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/position/positions":
+			writeMockResponse(w, testPositions)
+		case "/fapi/v1/trade/order":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			volumes = append(volumes, body["volume"])
+			writeMockResponse(w, `{"orderId":"1"}`)
+		}
+	})
+
+	// Without a step size, 2.5 contracts of the short round down to 2
+	if _, err := client.Futures().ClosePosition("E-BTC-USDT", decimal.RequireFromString("0.5")); err != nil {
+		t.Fatalf("ClosePosition() returned error: %v", err)
+	}
+	if len(volumes) != 2 || volumes[0] != "5" || volumes[1] != "2" {
+		t.Errorf("Expected whole contract volumes 5 and 2, got %v", volumes)
+	}
+
+	// Less than one contract of every position leaves nothing to close
+	if _, err := client.Futures().ClosePosition("E-BTC-USDT", decimal.RequireFromString("0.05")); !errors.Is(err, ErrNoPosition) {
+		t.Errorf("Expected ErrNoPosition for a fraction below one contract, got %v", err)
+	}
+}
+
+func TestFuturesAPI_ClosePositionNone(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeMockResponse(w, `[]`)
+	})
+
+	if _, err := client.Futures().ClosePosition("E-BTC-USDT", decimal.NewFromInt(1)); !errors.Is(err, ErrNoPosition) {
+		t.Errorf("Expected ErrNoPosition, got %v", err)
+	}
+}
+
+func TestFuturesAPI_CloseAllPositions(t *testing.T) {
+	var orders []map[string]interface{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/positionRisk":
+			writeMockResponse(w, testPositions)
+		case "/fapi/v1/trade/order":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			orders = append(orders, body)
+			// The first order is rejected, the rest still go out
+			if len(orders) == 1 {
+				fmt.Fprint(w, `{"code":"22","msg":"rejected"}`)
+				return
+			}
+			writeMockResponse(w, fmt.Sprintf(`{"orderId":"%d"}`, len(orders)))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	resp, err := client.Futures().CloseAllPositions()
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Errorf("Expected the rejected order error, got %v", err)
+	}
+	if len(orders) != 2 || len(resp) != 1 || resp[0].OrderID != "2" {
+		t.Fatalf("Expected 2 attempts and 1 placed order, got %d and %+v", len(orders), resp)
+	}
+	if orders[1]["type"] != OrderTypeMarket || orders[1]["volume"] != "5" || orders[1]["side"] != OrderSideBuy {
+		t.Errorf("Unexpected closing order %v", orders[1])
+	}
+}
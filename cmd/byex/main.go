@@ -0,0 +1,149 @@
+// Command byex exports account history from the exchange.
+//
+// Usage:
+//
+//	byex export -kind spot-trades -symbol BTCUSDT -format csv -o trades.csv
+//
+// Credentials are read from BYEX_API_KEY and BYEX_SECRET_KEY.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yanun0323/byex"
+)
+
+// futuresOrdersLimit is the number of futures orders requested, the history cannot be paged
+const futuresOrdersLimit = 100
+
+const usage = `usage: byex export [flags]
+
+Kinds:
+  spot-trades     spot trade history of -symbol
+  futures-trades  futures trade history of -symbol, a futures name like E-BTC-USDT
+  spot-orders     spot order history of -symbol
+  futures-orders  the latest futures orders of -symbol, the API cannot page further back
+  klines          the last -size klines of -symbol with -period
+
+Flags:
+`
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "export" {
+		fs, _ := exportFlags(os.Stderr)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err := runExport(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "byex:", err)
+		os.Exit(1)
+	}
+}
+
+type exportConfig struct {
+	kind    string
+	symbol  string
+	format  string
+	output  string
+	period  string
+	size    int
+	testnet bool
+}
+
+func exportFlags(output io.Writer) (*flag.FlagSet, *exportConfig) {
+	cfg := &exportConfig{}
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&cfg.kind, "kind", "", "what to export: spot-trades, futures-trades, spot-orders, futures-orders or klines")
+	fs.StringVar(&cfg.symbol, "symbol", "", "spot symbol or futures name")
+	fs.StringVar(&cfg.format, "format", string(byex.ExportFormatCSV), "output format: csv or jsonl")
+	fs.StringVar(&cfg.output, "o", "", "output file, stdout when empty")
+	fs.StringVar(&cfg.period, "period", "1min", "kline period")
+	fs.IntVar(&cfg.size, "size", 100, "number of klines")
+	fs.BoolVar(&cfg.testnet, "testnet", false, "use the testnet")
+	fs.Usage = func() {
+		fmt.Fprint(output, usage)
+		fs.PrintDefaults()
+	}
+	return fs, cfg
+}
+
+func runExport(args []string) error {
+	fs, cfg := exportFlags(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cfg.symbol == "" {
+		return fmt.Errorf("-symbol is required")
+	}
+
+	var out io.Writer = os.Stdout
+	if cfg.output != "" {
+		f, err := os.Create(cfg.output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	client := byex.NewClient("", "", byex.ClientOption{
+		Testnet:            cfg.testnet,
+		CredentialProvider: byex.NewEnvCredentialProvider(),
+	})
+	format := byex.ExportFormat(cfg.format)
+
+	switch cfg.kind {
+	case "spot-trades":
+		trades, err := byex.LoadSpotTrades(client.Exchange(), cfg.symbol)
+		if err != nil {
+			return err
+		}
+		return export(out, format, byex.NewExchangeTradeWriter, trades)
+	case "futures-trades":
+		trades, err := byex.LoadFuturesTrades(client.Futures(), cfg.symbol, "")
+		if err != nil {
+			return err
+		}
+		return export(out, format, byex.NewFuturesTradeWriter, trades)
+	case "spot-orders":
+		orders, err := byex.LoadSpotOrders(client.Exchange(), cfg.symbol)
+		if err != nil {
+			return err
+		}
+		return export(out, format, byex.NewExchangeOrderWriter, orders)
+	case "futures-orders":
+		orders, err := client.Futures().GetOrderHistory(cfg.symbol, futuresOrdersLimit)
+		if err != nil {
+			return err
+		}
+		if len(orders) >= futuresOrdersLimit {
+			fmt.Fprintf(os.Stderr, "byex: only the latest %d futures orders were exported, older orders are not available\n", len(orders))
+		}
+		return export(out, format, byex.NewFuturesOrderWriter, orders)
+	case "klines":
+		klines, err := client.Exchange().GetKlines(cfg.symbol, cfg.period, cfg.size)
+		if err != nil {
+			return err
+		}
+		return export(out, format, byex.NewKlineWriter, klines)
+	default:
+		return fmt.Errorf("unknown kind %q", cfg.kind)
+	}
+}
+
+func export[T any](w io.Writer, format byex.ExportFormat, newWriter func(io.Writer, byex.ExportFormat) (*byex.RecordWriter[T], error), records []T) error {
+	rw, err := newWriter(w, format)
+	if err != nil {
+		return err
+	}
+	return rw.WriteAll(records)
+}
@@ -0,0 +1,162 @@
+package byex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Default environment variables read by EnvCredentialProvider
+	DefaultAPIKeyEnv    = "BYEX_API_KEY"
+	DefaultSecretKeyEnv = "BYEX_SECRET_KEY"
+
+	defaultCredentialFileInterval = 5 * time.Second
+)
+
+// ErrEmptyCredentials is returned when a provider has no API key configured
+var ErrEmptyCredentials = errors.New("empty credentials")
+
+// Credentials represents an API key pair
+type Credentials struct {
+	APIKey    string `json:"apiKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// CredentialProvider provides the credentials used to sign requests.
+//
+// The client consults the provider once per request, so an implementation
+// can swap keys at any time without rebuilding the client.
+type CredentialProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// StaticCredentialProvider provides fixed credentials that can be replaced atomically
+type StaticCredentialProvider struct {
+	creds atomic.Value // Credentials
+}
+
+// NewStaticCredentialProvider creates a provider holding the given key pair
+func NewStaticCredentialProvider(apiKey, secretKey string) *StaticCredentialProvider {
+	p := &StaticCredentialProvider{}
+	p.Set(apiKey, secretKey)
+	return p
+}
+
+// Credentials returns the current key pair
+func (p *StaticCredentialProvider) Credentials() (Credentials, error) {
+	return p.creds.Load().(Credentials), nil
+}
+
+// Set replaces the key pair; requests already in flight keep the previous pair
+func (p *StaticCredentialProvider) Set(apiKey, secretKey string) {
+	p.creds.Store(Credentials{APIKey: apiKey, SecretKey: secretKey})
+}
+
+// EnvCredentialProvider reads credentials from environment variables on every request
+type EnvCredentialProvider struct {
+	APIKeyVar    string
+	SecretKeyVar string
+}
+
+// NewEnvCredentialProvider creates a provider reading BYEX_API_KEY and BYEX_SECRET_KEY
+func NewEnvCredentialProvider() *EnvCredentialProvider {
+	return &EnvCredentialProvider{
+		APIKeyVar:    DefaultAPIKeyEnv,
+		SecretKeyVar: DefaultSecretKeyEnv,
+	}
+}
+
+// Credentials returns the key pair currently set in the environment
+func (p *EnvCredentialProvider) Credentials() (Credentials, error) {
+	creds := Credentials{
+		APIKey:    os.Getenv(p.APIKeyVar),
+		SecretKey: os.Getenv(p.SecretKeyVar),
+	}
+
+	if creds.APIKey == "" {
+		return Credentials{}, fmt.Errorf("%w: environment variable %s is not set", ErrEmptyCredentials, p.APIKeyVar)
+	}
+
+	return creds, nil
+}
+
+// FileCredentialProvider reads credentials from a JSON file and reloads it when it changes.
+//
+// The file holds a single object: {"apiKey": "...", "secretKey": "..."}.
+// The file is checked for changes at most once per Interval. If a reload
+// fails (e.g. the file is being rewritten) the previous credentials are kept.
+type FileCredentialProvider struct {
+	Path     string
+	Interval time.Duration
+
+	mu        sync.Mutex
+	creds     Credentials
+	loaded    bool
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+}
+
+// NewFileCredentialProvider creates a provider watching the file at path
+func NewFileCredentialProvider(path string) *FileCredentialProvider {
+	return &FileCredentialProvider{
+		Path:     path,
+		Interval: defaultCredentialFileInterval,
+	}
+}
+
+// Credentials returns the key pair from the file, reloading it if it has changed
+func (p *FileCredentialProvider) Credentials() (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.loaded && now.Sub(p.lastCheck) < p.Interval {
+		return p.creds, nil
+	}
+	p.lastCheck = now
+
+	if err := p.reload(); err != nil && !p.loaded {
+		return Credentials{}, err
+	}
+
+	return p.creds, nil
+}
+
+// reload reads the file if its modification time or size changed since the last read
+func (p *FileCredentialProvider) reload() error {
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return fmt.Errorf("failed to stat credentials file: %w", err)
+	}
+
+	if p.loaded && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+
+	if creds.APIKey == "" {
+		return fmt.Errorf("%w: apiKey missing in %s", ErrEmptyCredentials, p.Path)
+	}
+
+	p.creds = creds
+	p.loaded = true
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+
+	return nil
+}
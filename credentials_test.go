@@ -0,0 +1,179 @@
+package byex
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStaticCredentialProvider(t *testing.T) {
+	p := NewStaticCredentialProvider("key1", "secret1")
+
+	creds, err := p.Credentials()
+	if err != nil {
+		t.Fatalf("Credentials() returned error: %v", err)
+	}
+	if creds.APIKey != "key1" || creds.SecretKey != "secret1" {
+		t.Errorf("Unexpected credentials: %+v", creds)
+	}
+
+	p.Set("key2", "secret2")
+
+	creds, _ = p.Credentials()
+	if creds.APIKey != "key2" || creds.SecretKey != "secret2" {
+		t.Errorf("Expected rotated credentials, got %+v", creds)
+	}
+}
+
+func TestEnvCredentialProvider(t *testing.T) {
+	t.Setenv(DefaultAPIKeyEnv, "")
+	t.Setenv(DefaultSecretKeyEnv, "")
+
+	p := NewEnvCredentialProvider()
+
+	if _, err := p.Credentials(); !errors.Is(err, ErrEmptyCredentials) {
+		t.Errorf("Expected ErrEmptyCredentials, got %v", err)
+	}
+
+	t.Setenv(DefaultAPIKeyEnv, "env_key")
+	t.Setenv(DefaultSecretKeyEnv, "env_secret")
+
+	creds, err := p.Credentials()
+	if err != nil {
+		t.Fatalf("Credentials() returned error: %v", err)
+	}
+	if creds.APIKey != "env_key" || creds.SecretKey != "env_secret" {
+		t.Errorf("Unexpected credentials: %+v", creds)
+	}
+}
+
+func TestFileCredentialProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+
+	p := NewFileCredentialProvider(path)
+	p.Interval = 0
+
+	if _, err := p.Credentials(); err == nil {
+		t.Error("Expected error for missing credentials file")
+	}
+
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write credentials file: %v", err)
+		}
+	}
+
+	writeFile(`{"apiKey":"file_key","secretKey":"file_secret"}`)
+	creds, err := p.Credentials()
+	if err != nil {
+		t.Fatalf("Credentials() returned error: %v", err)
+	}
+	if creds.APIKey != "file_key" {
+		t.Errorf("Expected file_key, got %s", creds.APIKey)
+	}
+
+	// A broken rewrite keeps the previous credentials
+	writeFile(`{"apiKey":`)
+	creds, err = p.Credentials()
+	if err != nil {
+		t.Fatalf("Credentials() returned error: %v", err)
+	}
+	if creds.APIKey != "file_key" {
+		t.Errorf("Expected previous credentials to be kept, got %s", creds.APIKey)
+	}
+
+	writeFile(`{"apiKey":"rotated_key","secretKey":"rotated_secret"}`)
+	creds, _ = p.Credentials()
+	if creds.APIKey != "rotated_key" || creds.SecretKey != "rotated_secret" {
+		t.Errorf("Expected rotated credentials, got %+v", creds)
+	}
+}
+
+func TestFileCredentialProvider_Interval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, []byte(`{"apiKey":"first","secretKey":"s"}`), 0o600); err != nil {
+		t.Fatalf("failed to write credentials file: %v", err)
+	}
+
+	p := NewFileCredentialProvider(path)
+	p.Interval = time.Hour
+
+	if creds, _ := p.Credentials(); creds.APIKey != "first" {
+		t.Fatalf("Expected first, got %s", creds.APIKey)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"apiKey":"second-key","secretKey":"s"}`), 0o600); err != nil {
+		t.Fatalf("failed to write credentials file: %v", err)
+	}
+
+	if creds, _ := p.Credentials(); creds.APIKey != "first" {
+		t.Errorf("Expected cached credentials within interval, got %s", creds.APIKey)
+	}
+}
+
+func TestClient_CredentialRotation(t *testing.T) {
+	provider := NewStaticCredentialProvider("key1", "secret1")
+
+	var mu sync.Mutex
+	var exchangeKeys, futuresKeys []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if key := r.URL.Query().Get("api_key"); key != "" {
+			exchangeKeys = append(exchangeKeys, key)
+		}
+		if key := r.Header.Get("X-CH-APIKEY"); key != "" {
+			futuresKeys = append(futuresKeys, key)
+		}
+		mu.Unlock()
+		writeMockResponse(w, `{}`)
+	}, ClientOption{CredentialProvider: provider})
+
+	exchange := client.Exchange()
+	futures := client.Futures()
+
+	if _, err := exchange.GetTicker("BTCUSDT"); err != nil {
+		t.Fatalf("GetTicker() returned error: %v", err)
+	}
+	if _, err := futures.GetTicker("E-BTC-USDT"); err != nil {
+		t.Fatalf("GetTicker() returned error: %v", err)
+	}
+
+	provider.Set("key2", "secret2")
+
+	if _, err := exchange.GetTicker("BTCUSDT"); err != nil {
+		t.Fatalf("GetTicker() returned error: %v", err)
+	}
+	if _, err := futures.GetTicker("E-BTC-USDT"); err != nil {
+		t.Fatalf("GetTicker() returned error: %v", err)
+	}
+
+	expected := []string{"key1", "key2"}
+	for i, key := range expected {
+		if exchangeKeys[i] != key {
+			t.Errorf("Expected exchange request %d to use %s, got %s", i, key, exchangeKeys[i])
+		}
+		if futuresKeys[i] != key {
+			t.Errorf("Expected futures request %d to use %s, got %s", i, key, futuresKeys[i])
+		}
+	}
+}
+
+func TestClient_CredentialProviderError(t *testing.T) {
+	t.Setenv(DefaultAPIKeyEnv, "")
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeMockResponse(w, `{}`)
+	}, ClientOption{CredentialProvider: NewEnvCredentialProvider()})
+
+	if _, err := client.Exchange().GetTicker("BTCUSDT"); !errors.Is(err, ErrEmptyCredentials) {
+		t.Errorf("Expected ErrEmptyCredentials, got %v", err)
+	}
+	if _, err := client.Futures().GetTicker("E-BTC-USDT"); !errors.Is(err, ErrEmptyCredentials) {
+		t.Errorf("Expected ErrEmptyCredentials, got %v", err)
+	}
+}
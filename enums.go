@@ -0,0 +1,360 @@
+package byex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidOrderSide is returned for a side other than BUY or SELL
+	ErrInvalidOrderSide = errors.New("invalid order side")
+	// ErrInvalidOrderType is returned for an unknown order type
+	ErrInvalidOrderType = errors.New("invalid order type")
+	// ErrInvalidPositionType is returned for a position type other than cross or isolated
+	ErrInvalidPositionType = errors.New("invalid position type")
+	// ErrInvalidVolumeType is returned for an unknown volume type
+	ErrInvalidVolumeType = errors.New("invalid volume type")
+)
+
+// OrderSide is the side of an order or trade, e.g. OrderSideBuy
+type OrderSide string
+
+// OrderType is the type of an order, e.g. OrderTypeLimit. Futures orders also use
+// the time in force values (IOC, FOK, POST_ONLY) as types.
+type OrderType string
+
+// OrderStatus is the status of an order, e.g. OrderStatusFilled
+type OrderStatus string
+
+// PositionType is the futures margin mode, sent as FuturesPositionTypeCross or FuturesPositionTypeIsolated
+type PositionType string
+
+// VolumeType tells whether the volume of a batch order is a quote or a base amount
+type VolumeType int
+
+const (
+	// VolumeTypeQuote means the volume is an amount of the quote asset
+	VolumeTypeQuote VolumeType = 1
+	// VolumeTypeBase means the volume is an amount of the base asset
+	VolumeTypeBase VolumeType = 2
+)
+
+// OrderStatusPendingCancel is an order whose cancellation has not completed yet
+const OrderStatusPendingCancel = "PENDING_CANCEL"
+
+// ParseOrderSide parses a side in any case. Unknown values are returned upper-cased with an error.
+func ParseOrderSide(s string) (OrderSide, error) {
+	switch v := strings.ToUpper(strings.TrimSpace(s)); v {
+	case OrderSideBuy, "BID":
+		return OrderSideBuy, nil
+	case OrderSideSell, "ASK":
+		return OrderSideSell, nil
+	default:
+		return OrderSide(v), fmt.Errorf("%w %q", ErrInvalidOrderSide, s)
+	}
+}
+
+// String returns the canonical spelling of known sides
+func (s OrderSide) String() string {
+	v, _ := ParseOrderSide(string(s))
+	return string(v)
+}
+
+// Valid reports whether s is BUY or SELL in any case
+func (s OrderSide) Valid() bool {
+	_, err := ParseOrderSide(string(s))
+	return err == nil
+}
+
+// UnmarshalJSON accepts a string or a number and keeps it as received
+func (s *OrderSide) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalStringOrNumber(data)
+	if err != nil {
+		return err
+	}
+
+	*s = OrderSide(raw)
+	return nil
+}
+
+// MarshalJSON writes the canonical spelling, so a side decoded as "buy" is sent as BUY
+func (s OrderSide) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// ParseOrderType parses a type in any case or its numeric code (1: limit, 2: market).
+// Unknown values are returned upper-cased with an error.
+func ParseOrderType(s string) (OrderType, error) {
+	switch v := strings.ToUpper(strings.TrimSpace(s)); v {
+	case OrderTypeLimit, "1":
+		return OrderTypeLimit, nil
+	case OrderTypeMarket, "2":
+		return OrderTypeMarket, nil
+	case string(TimeInForceIOC), string(TimeInForceFOK), string(TimeInForcePostOnly):
+		return OrderType(v), nil
+	default:
+		return OrderType(v), fmt.Errorf("%w %q", ErrInvalidOrderType, s)
+	}
+}
+
+// OrderTypeFromCode converts the numeric code used by batch orders
+func OrderTypeFromCode(code int) (OrderType, error) {
+	return ParseOrderType(strconv.Itoa(code))
+}
+
+// String returns the canonical spelling of known types
+func (t OrderType) String() string {
+	v, _ := ParseOrderType(string(t))
+	return string(v)
+}
+
+// Code returns the numeric code used by batch orders, or 0 for types without one
+func (t OrderType) Code() int {
+	switch OrderType(t.String()) {
+	case OrderTypeLimit:
+		return 1
+	case OrderTypeMarket:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Valid reports whether t is a known type in any case
+func (t OrderType) Valid() bool {
+	_, err := ParseOrderType(string(t))
+	return err == nil
+}
+
+// UnmarshalJSON accepts a string or a numeric code and keeps it as received
+func (t *OrderType) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalStringOrNumber(data)
+	if err != nil {
+		return err
+	}
+
+	*t = OrderType(raw)
+	return nil
+}
+
+// MarshalJSON writes the canonical spelling, so a numeric code is sent as its name
+func (t OrderType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// ParseOrderStatus parses the status spellings and numeric codes used by spot and futures APIs.
+// Unknown values are returned upper-cased with an error.
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	v := strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(s)), " ", "_")
+	if v == OrderStatusPendingCancel || v == "5" {
+		return OrderStatusPendingCancel, nil
+	}
+
+	status := normalizeOrderStatus(v)
+	if !isKnownOrderStatus(status) {
+		return OrderStatus(v), fmt.Errorf("%w %q", ErrUnknownOrderStatus, s)
+	}
+	return OrderStatus(status), nil
+}
+
+// OrderStatusFromCode converts a numeric status code
+func OrderStatusFromCode(code int) (OrderStatus, error) {
+	return ParseOrderStatus(strconv.Itoa(code))
+}
+
+// String returns the canonical spelling of known statuses
+func (s OrderStatus) String() string {
+	v, _ := ParseOrderStatus(string(s))
+	return string(v)
+}
+
+// Code returns the numeric status code, or 0 for statuses without one
+func (s OrderStatus) Code() int {
+	switch OrderStatus(s.String()) {
+	case OrderStatusNew:
+		return 1
+	case OrderStatusFilled:
+		return 2
+	case OrderStatusPartiallyFilled:
+		return 3
+	case OrderStatusCancelled:
+		return 4
+	case OrderStatusPendingCancel:
+		return 5
+	case OrderStatusRejected:
+		return 7
+	default:
+		return 0
+	}
+}
+
+// Valid reports whether s is a known status in any spelling
+func (s OrderStatus) Valid() bool {
+	_, err := ParseOrderStatus(string(s))
+	return err == nil
+}
+
+// IsFinal reports whether s is FILLED, CANCELLED or REJECTED in any spelling
+func (s OrderStatus) IsFinal() bool {
+	return isFinalOrderStatus(s.String())
+}
+
+// UnmarshalJSON accepts a string or a numeric code and keeps it as received
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalStringOrNumber(data)
+	if err != nil {
+		return err
+	}
+
+	*s = OrderStatus(raw)
+	return nil
+}
+
+// MarshalJSON writes the canonical spelling, so a numeric code is written as its name
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// ParsePositionType parses a position type code or name (CROSS, CROSSED, ISOLATED) in any case.
+// Unknown values are returned upper-cased with an error.
+func ParsePositionType(s string) (PositionType, error) {
+	switch v := strings.ToUpper(strings.TrimSpace(s)); v {
+	case FuturesPositionTypeCross, "CROSS", "CROSSED":
+		return FuturesPositionTypeCross, nil
+	case FuturesPositionTypeIsolated, "ISOLATED":
+		return FuturesPositionTypeIsolated, nil
+	default:
+		return PositionType(v), fmt.Errorf("%w %q", ErrInvalidPositionType, s)
+	}
+}
+
+// String returns the code of known position types
+func (p PositionType) String() string {
+	v, _ := ParsePositionType(string(p))
+	return string(v)
+}
+
+// Name returns CROSS or ISOLATED, or the raw value when it is unknown
+func (p PositionType) Name() string {
+	switch PositionType(p.String()) {
+	case FuturesPositionTypeCross:
+		return "CROSS"
+	case FuturesPositionTypeIsolated:
+		return "ISOLATED"
+	default:
+		return string(p)
+	}
+}
+
+// Valid reports whether p is a cross or isolated code or name
+func (p PositionType) Valid() bool {
+	_, err := ParsePositionType(string(p))
+	return err == nil
+}
+
+// UnmarshalJSON accepts a string or a numeric code and keeps it as received
+func (p *PositionType) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalStringOrNumber(data)
+	if err != nil {
+		return err
+	}
+
+	*p = PositionType(raw)
+	return nil
+}
+
+// MarshalJSON writes the code, so a name like CROSSED is sent as FuturesPositionTypeCross
+func (p PositionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// ParseVolumeType parses a volume type code or name (QUOTE, BASE)
+func ParseVolumeType(s string) (VolumeType, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "1", "QUOTE":
+		return VolumeTypeQuote, nil
+	case "2", "BASE":
+		return VolumeTypeBase, nil
+	default:
+		return 0, fmt.Errorf("%w %q", ErrInvalidVolumeType, s)
+	}
+}
+
+// String returns QUOTE or BASE
+func (v VolumeType) String() string {
+	switch v {
+	case VolumeTypeQuote:
+		return "QUOTE"
+	case VolumeTypeBase:
+		return "BASE"
+	default:
+		return strconv.Itoa(int(v))
+	}
+}
+
+// Valid reports whether v is a known volume type
+func (v VolumeType) Valid() bool {
+	return v == VolumeTypeQuote || v == VolumeTypeBase
+}
+
+// UnmarshalJSON accepts the numeric code, as a string or number, and names
+func (v *VolumeType) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalStringOrNumber(data)
+	if err != nil {
+		return err
+	}
+	if raw == "" {
+		*v = 0
+		return nil
+	}
+
+	parsed, err := ParseVolumeType(raw)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// MarshalJSON writes the numeric code the API expects
+func (v VolumeType) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(v))), nil
+}
+
+// unmarshalStringOrNumber reads a JSON string or number as a string. null reads as empty.
+func unmarshalStringOrNumber(data []byte) (string, error) {
+	if string(data) == "null" {
+		return "", nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s, nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", fmt.Errorf("expected a string or number, got %s", data)
+	}
+	return n.String(), nil
+}
+
+// validateOrder rejects an unknown side or type before the order is sent.
+// Missing values are left for the exchange to reject.
+func validateOrder(side OrderSide, orderType OrderType) error {
+	if side != "" {
+		if _, err := ParseOrderSide(string(side)); err != nil {
+			return err
+		}
+	}
+	if orderType != "" {
+		if _, err := ParseOrderType(string(orderType)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package byex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseEnums(t *testing.T) {
+	sides := map[string]OrderSide{"buy": OrderSideBuy, " Sell ": OrderSideSell, "BID": OrderSideBuy}
+	for in, want := range sides {
+		if got, err := ParseOrderSide(in); err != nil || got != want {
+			t.Errorf("ParseOrderSide(%q): expected %s, got %s (%v)", in, want, got, err)
+		}
+	}
+	if _, err := ParseOrderSide("hold"); !errors.Is(err, ErrInvalidOrderSide) {
+		t.Errorf("Expected ErrInvalidOrderSide, got %v", err)
+	}
+
+	types := map[string]OrderType{"limit": OrderTypeLimit, "2": OrderTypeMarket, "post_only": OrderType(TimeInForcePostOnly)}
+	for in, want := range types {
+		if got, err := ParseOrderType(in); err != nil || got != want {
+			t.Errorf("ParseOrderType(%q): expected %s, got %s (%v)", in, want, got, err)
+		}
+	}
+	if got, err := OrderTypeFromCode(1); err != nil || got != OrderTypeLimit || got.Code() != 1 {
+		t.Errorf("Expected code 1 to round-trip as LIMIT, got %s (%v)", got, err)
+	}
+
+	statuses := map[string]OrderStatus{"2": OrderStatusFilled, "part_filled": OrderStatusPartiallyFilled, "canceled": OrderStatusCancelled, "5": OrderStatusPendingCancel}
+	for in, want := range statuses {
+		if got, err := ParseOrderStatus(in); err != nil || got != want {
+			t.Errorf("ParseOrderStatus(%q): expected %s, got %s (%v)", in, want, got, err)
+		}
+	}
+	if _, err := ParseOrderStatus("LOST"); !errors.Is(err, ErrUnknownOrderStatus) {
+		t.Errorf("Expected ErrUnknownOrderStatus, got %v", err)
+	}
+	if OrderStatus("filled").Code() != 2 || !OrderStatus("canceled").IsFinal() || OrderStatus("new").IsFinal() {
+		t.Error("Expected status codes and IsFinal to tolerate spelling variants")
+	}
+
+	if got, _ := ParsePositionType("isolated"); got != FuturesPositionTypeIsolated || got.Name() != "ISOLATED" {
+		t.Errorf("Expected ISOLATED to parse as code 2, got %s", got)
+	}
+	if got, _ := ParseVolumeType("base"); got != VolumeTypeBase || got.String() != "BASE" {
+		t.Errorf("Expected BASE to parse as code 2, got %d", got)
+	}
+}
+
+func TestEnumsJSON(t *testing.T) {
+	var order FuturesOrder
+	if err := json.Unmarshal([]byte(`{"side":"sell","type":1,"status":2,"positionType":1}`), &order); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if order.Side != "sell" || order.Side.String() != OrderSideSell {
+		t.Errorf("Expected side kept as received and read as SELL, got %q", order.Side)
+	}
+	if order.Type.String() != OrderTypeLimit || order.Status.String() != OrderStatusFilled || order.PositionType.String() != FuturesPositionTypeCross {
+		t.Errorf("Expected numeric codes to be decoded, got %+v", order)
+	}
+
+	var batch BatchOrder
+	if err := json.Unmarshal([]byte(`{"side":"BUY","type":1,"volumeType":"2"}`), &batch); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if batch.VolumeType != VolumeTypeBase {
+		t.Errorf("Expected volume type 2, got %d", batch.VolumeType)
+	}
+	data, _ := json.Marshal(batch)
+	if string(data) != `{"volume":"0","price":"0","side":"BUY","type":1,"volumeType":2}` {
+		t.Errorf("Unexpected BatchOrder JSON %s", data)
+	}
+
+	// Values decoded in another spelling are written back canonically
+	data, _ = json.Marshal(order)
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+	if fields["side"] != OrderSideSell || fields["type"] != OrderTypeLimit || fields["status"] != OrderStatusFilled || fields["positionType"] != FuturesPositionTypeCross {
+		t.Errorf("Expected canonical values, got %s", data)
+	}
+
+	req := FuturesCreateOrderRequest{Side: "buy", Type: "2", PositionType: "CROSSED"}
+	data, _ = json.Marshal(req)
+	fields = nil
+	json.Unmarshal(data, &fields)
+	if fields["side"] != OrderSideBuy || fields["type"] != OrderTypeMarket || fields["positionType"] != FuturesPositionTypeCross {
+		t.Errorf("Expected the request to be sent with canonical values, got %s", data)
+	}
+
+	var condition FuturesConditionOrder
+	json.Unmarshal([]byte(`{"orderId":"1","status":4}`), &condition)
+	if !condition.Status.IsFinal() || condition.Status.String() != OrderStatusCancelled {
+		t.Errorf("Expected a numeric condition order status to be typed, got %q", condition.Status)
+	}
+}
+
+func TestCreateOrder_InvalidSide(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s", r.URL.Path)
+	})
+
+	_, err := client.Exchange().CreateOrder(CreateOrderRequest{Symbol: "BTCUSDT", Side: "HOLD", Type: OrderTypeLimit})
+	if !errors.Is(err, ErrInvalidOrderSide) {
+		t.Errorf("Expected ErrInvalidOrderSide, got %v", err)
+	}
+
+	_, err = client.Futures().CreateOrder(FuturesCreateOrderRequest{FuturesName: "E-BTC-USDT", Side: OrderSideBuy, Type: "STOP"})
+	if !errors.Is(err, ErrInvalidOrderType) {
+		t.Errorf("Expected ErrInvalidOrderType, got %v", err)
+	}
+}
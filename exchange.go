@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
@@ -27,8 +28,8 @@ func (e *ExchangeAPI) GetAllTicker() (*TickerListResponse, error) {
 		return nil, err
 	}
 
-	var result TickerListResponse
-	if err := json.Unmarshal(resp.Data.([]byte), &result); err != nil {
+	result, err := decodeData[TickerListResponse](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse ticker response: %w", err)
 	}
 
@@ -46,13 +47,8 @@ func (e *ExchangeAPI) GetTicker(symbol string) (*ExchangeTicker, error) {
 		return nil, err
 	}
 
-	var result ExchangeTicker
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[ExchangeTicker](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse ticker response: %w", err)
 	}
 
@@ -71,13 +67,8 @@ func (e *ExchangeAPI) GetDepth(symbol string, depth int) (*ExchangeDepth, error)
 		return nil, err
 	}
 
-	var result ExchangeDepth
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[ExchangeDepth](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse depth response: %w", err)
 	}
 
@@ -97,13 +88,8 @@ func (e *ExchangeAPI) GetKlines(symbol, period string, size int) ([]ExchangeKlin
 		return nil, err
 	}
 
-	var result []ExchangeKline
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]ExchangeKline](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse klines response: %w", err)
 	}
 
@@ -114,10 +100,26 @@ func (e *ExchangeAPI) GetKlines(symbol, period string, size int) ([]ExchangeKlin
 
 // CreateOrder creates a new order
 func (e *ExchangeAPI) CreateOrder(req CreateOrderRequest) (*OrderResponse, error) {
+	if err := validateOrder(req.Side, req.Type); err != nil {
+		return nil, err
+	}
+	clientOrderID, err := e.client.nextClientOrderID(req.ClientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	req.ClientOrderID = clientOrderID
+
+	limit := strings.EqualFold(string(req.Type), OrderTypeLimit)
+	if limit {
+		if err := e.checkSpotTimeInForce(req); err != nil {
+			return nil, err
+		}
+	}
+
 	params := map[string]string{
 		"symbol": req.Symbol,
-		"side":   req.Side,
-		"type":   req.Type,
+		"side":   req.Side.String(),
+		"type":   req.Type.String(),
 	}
 
 	if !req.Amount.IsZero() {
@@ -130,21 +132,32 @@ func (e *ExchangeAPI) CreateOrder(req CreateOrderRequest) (*OrderResponse, error
 		params["client_order_id"] = req.ClientOrderID
 	}
 
-	resp, err := e.client.doExchangeRequest("POST", "/open/api/create_order", params)
+	reservation, err := e.client.checkRisk(spotRiskOrder(req.Symbol, req.Side, req.Type, req.Price, req.Amount))
 	if err != nil {
 		return nil, err
 	}
 
-	var result OrderResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	resp, err := e.client.doExchangeRequest("POST", "/open/api/create_order", params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		reservation.release(err)
+		return nil, err
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
+	result, err := decodeData[OrderResponse](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
+	if result.ClientOrderID == "" {
+		result.ClientOrderID = req.ClientOrderID
+	}
+
+	if limit && (req.TimeInForce == TimeInForceIOC || req.TimeInForce == TimeInForceFOK) {
+		if err := e.cancelSpotResidual(req.Symbol, result.OrderID, req.TimeInForce); err != nil {
+			return &result, err
+		}
+	}
+
 	return &result, nil
 }
 
@@ -159,6 +172,17 @@ func (e *ExchangeAPI) CancelOrder(symbol, orderID string) error {
 	return err
 }
 
+// CancelOrderByClientOrderID cancels an existing order by its client order ID
+func (e *ExchangeAPI) CancelOrderByClientOrderID(symbol, clientOrderID string) error {
+	params := map[string]string{
+		"symbol":          symbol,
+		"client_order_id": clientOrderID,
+	}
+
+	_, err := e.client.doExchangeRequest("POST", "/open/api/cancel_order", params)
+	return err
+}
+
 // CancelAllOrders cancels all orders for a symbol
 func (e *ExchangeAPI) CancelAllOrders(symbol string) error {
 	params := map[string]string{
@@ -175,14 +199,42 @@ func (e *ExchangeAPI) BatchCreateOrders(req BatchOrderRequest) error {
 		"symbol": req.Symbol,
 	}
 
+	risks := make([]riskOrder, len(req.Orders))
+	for i, order := range req.Orders {
+		if err := validateOrder(order.Side, order.Type); err != nil {
+			return err
+		}
+		risks[i] = spotRiskOrder(req.Symbol, order.Side, order.Type, order.Price, order.Amount)
+	}
+	orders := make([]CreateOrderRequest, len(req.Orders))
+	for i, order := range req.Orders {
+		if order.TimeInForce != "" && order.TimeInForce != TimeInForceGTC {
+			return fmt.Errorf("%w: %s is not supported for batch orders", ErrInvalidTimeInForce, order.TimeInForce)
+		}
+		order.TimeInForce = ""
+
+		clientOrderID, err := e.client.nextClientOrderID(order.ClientOrderID)
+		if err != nil {
+			return err
+		}
+		order.ClientOrderID = clientOrderID
+		orders[i] = order
+	}
+
 	// Convert orders to JSON string
-	ordersJSON, err := json.Marshal(req.Orders)
+	ordersJSON, err := json.Marshal(orders)
 	if err != nil {
 		return fmt.Errorf("failed to marshal orders: %w", err)
 	}
 	params["orders_data"] = string(ordersJSON)
 
+	reservation, err := e.client.checkRisk(risks...)
+	if err != nil {
+		return err
+	}
+
 	_, err = e.client.doExchangeRequest("POST", "/open/api/mass_replace", params)
+	reservation.release(err)
 	return err
 }
 
@@ -204,13 +256,8 @@ func (e *ExchangeAPI) GetCurrentOrders(symbol string, pageSize, page int) (*Orde
 		return nil, err
 	}
 
-	var result OrderListResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[OrderListResponse](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse orders response: %w", err)
 	}
 
@@ -235,13 +282,8 @@ func (e *ExchangeAPI) GetOrderHistory(symbol string, pageSize, page int) (*Order
 		return nil, err
 	}
 
-	var result OrderListResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[OrderListResponse](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse orders response: %w", err)
 	}
 
@@ -260,13 +302,28 @@ func (e *ExchangeAPI) GetOrderInfo(symbol, orderID string) (*ExchangeOrder, erro
 		return nil, err
 	}
 
-	var result ExchangeOrder
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[ExchangeOrder](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
+	return &result, nil
+}
+
+// GetOrderInfoByClientOrderID gets specific order information by its client order ID
+func (e *ExchangeAPI) GetOrderInfoByClientOrderID(symbol, clientOrderID string) (*ExchangeOrder, error) {
+	params := map[string]string{
+		"symbol":          symbol,
+		"client_order_id": clientOrderID,
+	}
+
+	resp, err := e.client.doExchangeRequest("GET", "/open/api/order_info", params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeData[ExchangeOrder](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
@@ -291,13 +348,8 @@ func (e *ExchangeAPI) GetTrades(symbol string, pageSize, page int) (*TradeListRe
 		return nil, err
 	}
 
-	var result TradeListResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[TradeListResponse](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse trades response: %w", err)
 	}
 
@@ -313,13 +365,8 @@ func (e *ExchangeAPI) GetAccount() (*ExchangeAccount, error) {
 		return nil, err
 	}
 
-	var result ExchangeAccount
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[ExchangeAccount](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse account response: %w", err)
 	}
 
@@ -342,13 +389,8 @@ func (e *ExchangeAPI) GetBalance(coins []string) ([]CoinBalance, error) {
 		return nil, err
 	}
 
-	var account ExchangeAccount
-	dataBytes, err := json.Marshal(resp.Data)
+	account, err := decodeData[ExchangeAccount](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &account); err != nil {
 		return nil, fmt.Errorf("failed to parse account response: %w", err)
 	}
 
@@ -385,13 +427,8 @@ func (e *ExchangeAPI) GetAllTradingRecords(symbol string, pageSize, page int, id
 		return nil, err
 	}
 
-	var result TradeListResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[TradeListResponse](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse all trades response: %w", err)
 	}
 
@@ -405,13 +442,8 @@ func (e *ExchangeAPI) GetMarketPrices() (map[string]decimal.Decimal, error) {
 		return nil, err
 	}
 
-	var result map[string]decimal.Decimal
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[map[string]decimal.Decimal](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse market prices response: %w", err)
 	}
 
@@ -424,25 +456,48 @@ func (e *ExchangeAPI) BatchPlaceOrders(symbol string, orderList []BatchOrder) (*
 		"symbol": symbol,
 	}
 
+	risks := make([]riskOrder, len(orderList))
+	for i, order := range orderList {
+		var orderType OrderType = OrderTypeLimit
+		if order.Type == 2 {
+			orderType = OrderTypeMarket
+		}
+		risks[i] = spotRiskOrder(symbol, order.Side, orderType, order.Price, order.Volume)
+		// A volume type of 1 sizes the order in the quote currency
+		if order.VolumeType == VolumeTypeQuote {
+			risks[i].amount, risks[i].notional = decimal.Zero, order.Volume
+		}
+	}
+	orders := make([]BatchOrder, len(orderList))
+	for i, order := range orderList {
+		clientOrderID, err := e.client.nextClientOrderID(order.ClientOrderID)
+		if err != nil {
+			return nil, err
+		}
+		order.ClientOrderID = clientOrderID
+		orders[i] = order
+	}
+
 	// Convert orderList to JSON string
-	orderListJSON, err := json.Marshal(orderList)
+	orderListJSON, err := json.Marshal(orders)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal order list: %w", err)
 	}
 	params["orderList"] = string(orderListJSON)
 
-	resp, err := e.client.doExchangeRequest("POST", "/open/api/batchOrders", params)
+	reservation, err := e.client.checkRisk(risks...)
 	if err != nil {
 		return nil, err
 	}
 
-	var result BatchOrderResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	resp, err := e.client.doExchangeRequest("POST", "/open/api/batchOrders", params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		reservation.release(err)
+		return nil, err
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
+	result, err := decodeData[BatchOrderResponse](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse batch orders response: %w", err)
 	}
 
@@ -478,13 +533,8 @@ func (e *ExchangeAPI) GetOrderDetail(symbol, orderID string) (*ExchangeOrderDeta
 		return nil, err
 	}
 
-	var result ExchangeOrderDetail
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[ExchangeOrderDetail](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse order detail response: %w", err)
 	}
 
@@ -493,11 +543,20 @@ func (e *ExchangeAPI) GetOrderDetail(symbol, orderID string) (*ExchangeOrderDeta
 
 // ReplaceOrder replaces an existing order
 func (e *ExchangeAPI) ReplaceOrder(req ReplaceOrderRequest) (*OrderResponse, error) {
+	if err := validateOrder(req.Side, req.Type); err != nil {
+		return nil, err
+	}
+	clientOrderID, err := e.client.nextClientOrderID(req.ClientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	req.ClientOrderID = clientOrderID
+
 	params := map[string]string{
 		"symbol":       req.Symbol,
 		"cancel_order": req.CancelOrderID,
-		"side":         req.Side,
-		"type":         req.Type,
+		"side":         req.Side.String(),
+		"type":         req.Type.String(),
 	}
 
 	if !req.Amount.IsZero() {
@@ -510,21 +569,26 @@ func (e *ExchangeAPI) ReplaceOrder(req ReplaceOrderRequest) (*OrderResponse, err
 		params["client_order_id"] = req.ClientOrderID
 	}
 
-	resp, err := e.client.doExchangeRequest("POST", "/open/api/replace_order", params)
+	reservation, err := e.client.checkRisk(spotRiskOrder(req.Symbol, req.Side, req.Type, req.Price, req.Amount))
 	if err != nil {
 		return nil, err
 	}
 
-	var result OrderResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	resp, err := e.client.doExchangeRequest("POST", "/open/api/replace_order", params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		reservation.release(err)
+		return nil, err
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
+	result, err := decodeData[OrderResponse](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse replace order response: %w", err)
 	}
 
+	if result.ClientOrderID == "" {
+		result.ClientOrderID = req.ClientOrderID
+	}
+
 	return &result, nil
 }
 
@@ -535,13 +599,8 @@ func (e *ExchangeAPI) GetSymbolsCharge() ([]SymbolCharge, error) {
 		return nil, err
 	}
 
-	var result []SymbolCharge
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]SymbolCharge](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse symbols charge response: %w", err)
 	}
 
@@ -559,13 +618,8 @@ func (e *ExchangeAPI) GetLeverageFinanceBalance(symbol string) (*LeverageFinance
 		return nil, err
 	}
 
-	var result LeverageFinanceBalance
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[LeverageFinanceBalance](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse leverage finance balance response: %w", err)
 	}
 
@@ -845,3 +845,27 @@ func TestExchangeAPI_AllMethodsExist(t *testing.T) {
 
 	t.Logf("All %d exchange API methods exist", len(methods))
 }
+
+func TestExchangeAPI_GetAllTicker_Decode(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open/api/get_allticker" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		writeMockResponse(w, `{"date":1640995200,"ticker":[{"symbol":"BTCUSDT","high":"50000","low":"45000","last":"48000","vol":"1000","amount":"48000000","buy":"47950","sell":"48050","newCoinFlag":0,"change":"1000","rose":"0.02"}]}`)
+	})
+
+	result, err := client.Exchange().GetAllTicker()
+	if err != nil {
+		t.Fatalf("GetAllTicker() returned error: %v", err)
+	}
+
+	if result.Date.Int64() != 1640995200 {
+		t.Errorf("Expected date 1640995200, got %d", result.Date.Int64())
+	}
+	if len(result.Ticker) != 1 || result.Ticker[0].Symbol != "BTCUSDT" {
+		t.Fatalf("Unexpected tickers: %+v", result.Ticker)
+	}
+	if !result.Ticker[0].Last.Equal(decimal.NewFromInt(48000)) {
+		t.Errorf("Expected last 48000, got %s", result.Ticker[0].Last)
+	}
+}
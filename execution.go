@@ -0,0 +1,647 @@
+package byex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultExecutionPollInterval = time.Second
+	// maxExecutionRefreshErrors is how many polls of a child order may fail in a row
+	maxExecutionRefreshErrors = 3
+)
+
+var (
+	// ErrExecutionIncomplete is returned when an execution ends with a remainder too small to place
+	ErrExecutionIncomplete = errors.New("execution ended with an unfilled remainder")
+)
+
+// ExecutionState is the state of an execution algorithm
+type ExecutionState string
+
+const (
+	ExecutionRunning   ExecutionState = "RUNNING"
+	ExecutionPaused    ExecutionState = "PAUSED"
+	ExecutionCompleted ExecutionState = "COMPLETED"
+	ExecutionCancelled ExecutionState = "CANCELLED"
+	ExecutionFailed    ExecutionState = "FAILED"
+)
+
+// ParentOrder describes the full order worked by an execution algorithm
+type ParentOrder struct {
+	Market OrderMarket
+	// Symbol is the spot symbol or the futures name
+	Symbol string
+	Side   OrderSide
+	Amount decimal.Decimal
+	// LimitPrice makes child orders limit orders at this price. Zero sends market orders.
+	LimitPrice decimal.Decimal
+
+	// Open and PositionType are used for futures child orders
+	Open         string
+	PositionType PositionType
+}
+
+// ExecutionOptions are shared by all execution algorithms
+type ExecutionOptions struct {
+	// Rules constrains child order price and amount. When nil, spot rules are
+	// looked up with GetSymbolsCharge. Futures executions require it.
+	Rules *SymbolCharge
+	// PollInterval is how often working child orders are checked with GetOrderInfo
+	PollInterval time.Duration
+	// OnProgress is called from the execution goroutine after every child order
+	// placement or update and every state change. It may call Pause, Resume and Cancel.
+	OnProgress func(ExecutionProgress)
+}
+
+// TWAPConfig configures a time-weighted average price execution
+type TWAPConfig struct {
+	ExecutionOptions
+
+	// Duration is spread evenly across Slices child orders. Unfilled amounts of
+	// a slice are cancelled at the end of its interval and carried to later slices.
+	Duration time.Duration
+	Slices   int
+}
+
+// IcebergConfig configures an iceberg execution
+type IcebergConfig struct {
+	ExecutionOptions
+
+	// DisplayAmount is the size of each visible child order
+	DisplayAmount decimal.Decimal
+}
+
+// ChildOrder is an order placed by an execution algorithm
+type ChildOrder struct {
+	OrderID       string
+	ClientOrderID string
+	Price         decimal.Decimal
+	Amount        decimal.Decimal
+	FilledAmount  decimal.Decimal
+	AvgPrice      decimal.Decimal
+	Status        string
+	PlacedAt      time.Time
+}
+
+// ExecutionProgress is a snapshot of an execution
+type ExecutionProgress struct {
+	State        ExecutionState
+	Amount       decimal.Decimal
+	FilledAmount decimal.Decimal
+	AvgPrice     decimal.Decimal
+	Children     []ChildOrder
+}
+
+// Remaining returns the amount not filled yet
+func (p ExecutionProgress) Remaining() decimal.Decimal {
+	return p.Amount.Sub(p.FilledAmount)
+}
+
+// Executor runs execution algorithms on top of CreateOrder
+type Executor struct {
+	exchange *ExchangeAPI
+	futures  *FuturesAPI
+}
+
+// NewExecutor creates an executor.
+// exchange or futures may be nil if only the other market is used.
+func NewExecutor(exchange *ExchangeAPI, futures *FuturesAPI) *Executor {
+	return &Executor{
+		exchange: exchange,
+		futures:  futures,
+	}
+}
+
+// TWAP starts slicing parent into cfg.Slices child orders placed evenly over cfg.Duration
+func (x *Executor) TWAP(ctx context.Context, parent ParentOrder, cfg TWAPConfig) (*Execution, error) {
+	if cfg.Slices <= 0 || cfg.Duration <= 0 {
+		return nil, errors.New("twap requires positive duration and slices")
+	}
+
+	e, err := x.newExecution(ctx, parent, cfg.ExecutionOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	go e.run(func() error { return e.twap(cfg) })
+	return e, nil
+}
+
+// Iceberg starts working parent as a sequence of child orders of cfg.DisplayAmount.
+// The next child is placed once the previous one is complete.
+func (x *Executor) Iceberg(ctx context.Context, parent ParentOrder, cfg IcebergConfig) (*Execution, error) {
+	if !cfg.DisplayAmount.IsPositive() {
+		return nil, errors.New("iceberg requires a positive display amount")
+	}
+	if parent.LimitPrice.IsZero() {
+		return nil, errors.New("iceberg requires a limit price")
+	}
+
+	e, err := x.newExecution(ctx, parent, cfg.ExecutionOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	go e.run(func() error { return e.iceberg(cfg) })
+	return e, nil
+}
+
+func (x *Executor) newExecution(ctx context.Context, parent ParentOrder, opts ExecutionOptions) (*Execution, error) {
+	if !parent.Amount.IsPositive() {
+		return nil, errors.New("parent order amount must be positive")
+	}
+
+	switch parent.Market {
+	case OrderMarketSpot:
+		if x.exchange == nil {
+			return nil, errors.New("executor has no exchange API")
+		}
+		if opts.Rules == nil {
+			rules, err := x.spotRules(parent.Symbol)
+			if err != nil {
+				return nil, err
+			}
+			opts.Rules = rules
+		}
+	case OrderMarketFutures:
+		if x.futures == nil {
+			return nil, errors.New("executor has no futures API")
+		}
+		if opts.Rules == nil {
+			return nil, errors.New("futures executions require rules for the contract step and tick sizes")
+		}
+	default:
+		return nil, fmt.Errorf("unknown market %s", parent.Market)
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultExecutionPollInterval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &Execution{
+		executor: x,
+		parent:   parent,
+		opts:     opts,
+		ctx:      ctx,
+		cancel:   cancel,
+		state:    ExecutionRunning,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// spotRules looks up the trading rules of a spot symbol
+func (x *Executor) spotRules(symbol string) (*SymbolCharge, error) {
+	charges, err := x.exchange.GetSymbolsCharge()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol rules: %w", err)
+	}
+
+	for _, charge := range charges {
+		if strings.EqualFold(charge.Symbol, symbol) {
+			charge := charge
+			return &charge, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no trading rules for symbol %s", symbol)
+}
+
+// Execution is a running execution algorithm
+type Execution struct {
+	executor *Executor
+	parent   ParentOrder
+	opts     ExecutionOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	state    ExecutionState
+	paused   chan struct{} // closed on resume, nil while running
+	children []*ChildOrder
+	err      error
+}
+
+// Pause stops placing new child orders. Working child orders stay on the book.
+// The state change is reported to OnProgress by the execution goroutine.
+func (e *Execution) Pause() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state == ExecutionRunning {
+		e.state = ExecutionPaused
+		e.paused = make(chan struct{})
+	}
+}
+
+// Resume continues a paused execution
+func (e *Execution) Resume() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state == ExecutionPaused {
+		e.state = ExecutionRunning
+		close(e.paused)
+		e.paused = nil
+	}
+}
+
+// Cancel stops the execution and cancels its working child order
+func (e *Execution) Cancel() {
+	e.cancel()
+}
+
+// Done is closed when the execution has finished
+func (e *Execution) Done() <-chan struct{} {
+	return e.done
+}
+
+// Wait blocks until the execution has finished and returns its error, if any
+func (e *Execution) Wait() error {
+	<-e.done
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// Progress returns a snapshot of the execution
+func (e *Execution) Progress() ExecutionProgress {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	p := ExecutionProgress{
+		State:  e.state,
+		Amount: e.parent.Amount,
+	}
+
+	notional := decimal.Zero
+	for _, child := range e.children {
+		p.Children = append(p.Children, *child)
+		p.FilledAmount = p.FilledAmount.Add(child.FilledAmount)
+		notional = notional.Add(child.FilledAmount.Mul(child.AvgPrice))
+	}
+	if p.FilledAmount.IsPositive() {
+		p.AvgPrice = notional.Div(p.FilledAmount)
+	}
+
+	return p
+}
+
+func (e *Execution) notify() {
+	if e.opts.OnProgress != nil {
+		e.opts.OnProgress(e.Progress())
+	}
+}
+
+// run executes algo and records the final state
+func (e *Execution) run(algo func() error) {
+	defer close(e.done)
+	defer e.cancel()
+
+	err := algo()
+
+	e.mu.Lock()
+	switch {
+	case err == nil:
+		e.state = ExecutionCompleted
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		e.state = ExecutionCancelled
+	default:
+		e.state = ExecutionFailed
+		e.err = err
+	}
+	e.mu.Unlock()
+
+	e.notify()
+}
+
+// twap places evenly spaced slices and carries unfilled amounts to the next slice
+func (e *Execution) twap(cfg TWAPConfig) error {
+	interval := cfg.Duration / time.Duration(cfg.Slices)
+
+	for i := 0; i < cfg.Slices; i++ {
+		if err := e.waitIfPaused(); err != nil {
+			return err
+		}
+
+		remaining := e.remaining()
+		if !remaining.IsPositive() {
+			return nil
+		}
+		size := remaining
+		if left := cfg.Slices - i; left > 1 {
+			size = remaining.Div(decimal.NewFromInt(int64(left)))
+		}
+
+		child, err := e.place(size)
+		if err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(interval)
+		if err := e.workUntil(child, deadline); err != nil {
+			return err
+		}
+		if child != nil && !isFinalOrderStatus(child.Status) {
+			if err := e.cancelChild(child); err != nil {
+				return err
+			}
+		}
+	}
+
+	return e.checkRemainder()
+}
+
+// iceberg places one visible child at a time until the parent is filled
+func (e *Execution) iceberg(cfg IcebergConfig) error {
+	for {
+		if err := e.waitIfPaused(); err != nil {
+			return err
+		}
+
+		remaining := e.remaining()
+		size := cfg.DisplayAmount
+		if remaining.LessThan(size) {
+			size = remaining
+		}
+
+		child, err := e.place(size)
+		if err != nil {
+			return err
+		}
+		if child == nil {
+			// Nothing tradable is left
+			return e.checkRemainder()
+		}
+
+		if err := e.workUntil(child, time.Time{}); err != nil {
+			return err
+		}
+	}
+}
+
+// workUntil polls child until it is final or deadline passes. A zero deadline waits for completion.
+// When the execution is cancelled or the child cannot be polled, the child is cancelled before returning.
+func (e *Execution) workUntil(child *ChildOrder, deadline time.Time) error {
+	failures := 0
+	for {
+		wait := e.opts.PollInterval
+		if !deadline.IsZero() {
+			until := time.Until(deadline)
+			if until <= 0 {
+				return nil
+			}
+			if until < wait {
+				wait = until
+			}
+		}
+
+		if child == nil || isFinalOrderStatus(child.Status) {
+			if deadline.IsZero() {
+				return nil
+			}
+			// Keep the slice schedule even when the child completed early
+			if err := e.sleep(time.Until(deadline)); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		if err := e.sleep(wait); err != nil {
+			if cancelErr := e.cancelChild(child); cancelErr != nil {
+				return errors.Join(err, cancelErr)
+			}
+			return err
+		}
+
+		// A transient polling error is retried on the next interval
+		if err := e.refresh(child); err != nil {
+			failures++
+			if failures < maxExecutionRefreshErrors {
+				continue
+			}
+			if cancelErr := e.cancelChild(child); cancelErr != nil {
+				return errors.Join(err, cancelErr)
+			}
+			return err
+		}
+		failures = 0
+	}
+}
+
+// waitIfPaused blocks while the execution is paused
+func (e *Execution) waitIfPaused() error {
+	e.mu.Lock()
+	paused := e.paused
+	e.mu.Unlock()
+
+	if paused == nil {
+		return e.ctx.Err()
+	}
+
+	e.notify()
+	select {
+	case <-paused:
+		e.notify()
+		return e.ctx.Err()
+	case <-e.ctx.Done():
+		return e.ctx.Err()
+	}
+}
+
+func (e *Execution) sleep(d time.Duration) error {
+	if d <= 0 {
+		return e.ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-e.ctx.Done():
+		return e.ctx.Err()
+	}
+}
+
+// remaining returns the parent amount not filled by any child
+func (e *Execution) remaining() decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	remaining := e.parent.Amount
+	for _, child := range e.children {
+		remaining = remaining.Sub(child.FilledAmount)
+	}
+	return remaining
+}
+
+// checkRemainder reports an unfilled remainder left when the algorithm ends
+func (e *Execution) checkRemainder() error {
+	if remaining := e.remaining(); remaining.IsPositive() {
+		return fmt.Errorf("%w: %s of %s", ErrExecutionIncomplete, remaining, e.parent.Symbol)
+	}
+	return nil
+}
+
+// place rounds and places a child order. It returns nil if the rounded amount is not tradable.
+func (e *Execution) place(amount decimal.Decimal) (*ChildOrder, error) {
+	amount, price := e.roundOrder(amount)
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	x := e.executor
+	child := &ChildOrder{
+		Price:    price,
+		Amount:   amount,
+		Status:   OrderStatusNew,
+		PlacedAt: time.Now(),
+	}
+
+	var orderType OrderType = OrderTypeMarket
+	if price.IsPositive() {
+		orderType = OrderTypeLimit
+	}
+
+	var (
+		resp *OrderResponse
+		err  error
+	)
+	switch e.parent.Market {
+	case OrderMarketSpot:
+		resp, err = x.exchange.CreateOrder(CreateOrderRequest{
+			Symbol: e.parent.Symbol,
+			Side:   e.parent.Side,
+			Type:   orderType,
+			Amount: amount,
+			Price:  price,
+		})
+	case OrderMarketFutures:
+		resp, err = x.futures.CreateOrder(FuturesCreateOrderRequest{
+			FuturesName:  e.parent.Symbol,
+			Type:         orderType,
+			Side:         e.parent.Side,
+			Open:         e.parent.Open,
+			PositionType: e.parent.PositionType,
+			Price:        price,
+			Volume:       amount,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to place child order: %w", err)
+	}
+
+	child.OrderID = resp.OrderID
+	child.ClientOrderID = resp.ClientOrderID
+
+	e.mu.Lock()
+	e.children = append(e.children, child)
+	e.mu.Unlock()
+	e.notify()
+
+	return child, nil
+}
+
+// roundOrder applies the step and tick constraints of the rules.
+// Amounts are rounded down, and limit prices are rounded towards the passive side.
+func (e *Execution) roundOrder(amount decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	price := e.parent.LimitPrice
+	rules := e.opts.Rules
+	if rules == nil {
+		return amount, price
+	}
+
+	if rules.MaxQty.IsPositive() && amount.GreaterThan(rules.MaxQty) {
+		amount = rules.MaxQty
+	}
+	if rules.StepSize.IsPositive() {
+		amount = amount.Div(rules.StepSize).Floor().Mul(rules.StepSize)
+	}
+	if rules.MinQty.IsPositive() && amount.LessThan(rules.MinQty) {
+		amount = decimal.Zero
+	}
+
+	if price.IsPositive() && rules.TickSize.IsPositive() {
+		ticks := price.Div(rules.TickSize)
+		if e.parent.Side.String() == OrderSideBuy {
+			ticks = ticks.Floor()
+		} else {
+			ticks = ticks.Ceil()
+		}
+		price = ticks.Mul(rules.TickSize)
+	}
+
+	return amount, price
+}
+
+// refresh updates child from GetOrderInfo
+func (e *Execution) refresh(child *ChildOrder) error {
+	var order ManagedOrder
+	switch e.parent.Market {
+	case OrderMarketSpot:
+		info, err := e.executor.exchange.GetOrderInfo(e.parent.Symbol, child.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get child order %s: %w", child.OrderID, err)
+		}
+		order = managedFromExchangeOrder(e.parent.Symbol, *info)
+	case OrderMarketFutures:
+		info, err := e.executor.futures.GetOrderInfo(e.parent.Symbol, child.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get child order %s: %w", child.OrderID, err)
+		}
+		order = managedFromFuturesOrder(e.parent.Symbol, *info)
+	}
+
+	e.mu.Lock()
+	child.FilledAmount = order.FilledAmount
+	if order.AvgPrice.IsPositive() {
+		child.AvgPrice = order.AvgPrice
+	} else if order.FilledAmount.IsPositive() {
+		child.AvgPrice = child.Price
+	}
+	if order.Status != "" {
+		child.Status = string(order.Status)
+	}
+	e.mu.Unlock()
+	e.notify()
+
+	return nil
+}
+
+// cancelChild cancels a working child and records its final fill
+func (e *Execution) cancelChild(child *ChildOrder) error {
+	var err error
+	switch e.parent.Market {
+	case OrderMarketSpot:
+		err = e.executor.exchange.CancelOrder(e.parent.Symbol, child.OrderID)
+	case OrderMarketFutures:
+		err = e.executor.futures.CancelOrder(e.parent.Symbol, child.OrderID)
+	}
+
+	if refreshErr := e.refresh(child); refreshErr != nil {
+		return errors.Join(err, refreshErr)
+	}
+	if err != nil && !isFinalOrderStatus(child.Status) {
+		return fmt.Errorf("failed to cancel child order %s: %w", child.OrderID, err)
+	}
+
+	e.mu.Lock()
+	if !isFinalOrderStatus(child.Status) {
+		child.Status = OrderStatusCancelled
+	}
+	e.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,323 @@
+package byex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// mockVenue keeps child order state for the execution tests
+type mockVenue struct {
+	mu        sync.Mutex
+	orders    map[string]*ExchangeOrder
+	placed    []*ExchangeOrder
+	cancelled []string
+	fill      func(n int, amount decimal.Decimal) (decimal.Decimal, string)
+}
+
+func (v *mockVenue) create(amount, price decimal.Decimal) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	id := fmt.Sprint(len(v.placed) + 1)
+	filled, status := v.fill(len(v.placed), amount)
+	order := &ExchangeOrder{ID: id, Amount: amount, Price: price, FilledAmount: filled, AvgPrice: price, Status: OrderStatus(status)}
+	v.orders[id] = order
+	v.placed = append(v.placed, order)
+	return id
+}
+
+func (v *mockVenue) cancel(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.cancelled = append(v.cancelled, id)
+	if order, ok := v.orders[id]; ok && order.Status != OrderStatusFilled {
+		order.Status = "CANCELED"
+	}
+}
+
+func (v *mockVenue) info(id string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	data, _ := json.Marshal(v.orders[id])
+	return string(data)
+}
+
+// futuresInfo renders the order the way the futures order info endpoint does
+func (v *mockVenue) futuresInfo(id string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	order := v.orders[id]
+	data, _ := json.Marshal(FuturesOrder{
+		OrderID:     order.ID,
+		Price:       order.Price,
+		Volume:      order.Amount,
+		ExecutedQty: order.FilledAmount,
+		AvgPrice:    order.AvgPrice,
+		Status:      order.Status,
+	})
+	return string(data)
+}
+
+func (v *mockVenue) count() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.placed)
+}
+
+func TestExecutor_TWAP(t *testing.T) {
+	venue := &mockVenue{
+		orders: make(map[string]*ExchangeOrder),
+		fill: func(n int, amount decimal.Decimal) (decimal.Decimal, string) {
+			// The first slice only fills partially
+			if n == 0 {
+				return decimal.RequireFromString("0.1"), "PART_FILLED"
+			}
+			return amount, "FILLED"
+		},
+	}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/common/symbols":
+			writeMockResponse(w, `[{"symbol":"BTCUSDT","tickSize":"0.1","stepSize":"0.01","minQty":"0.01"}]`)
+		case "/open/api/create_order":
+			r.ParseForm()
+			if r.PostForm.Get("type") != OrderTypeLimit {
+				t.Errorf("Expected limit child order, got %s", r.PostForm.Get("type"))
+			}
+			id := venue.create(decimal.RequireFromString(r.PostForm.Get("volume")), decimal.RequireFromString(r.PostForm.Get("price")))
+			writeMockResponse(w, `{"orderId":"`+id+`"}`)
+		case "/open/api/order_info":
+			writeMockResponse(w, venue.info(r.URL.Query().Get("order_id")))
+		case "/open/api/cancel_order":
+			r.ParseForm()
+			venue.cancel(r.PostForm.Get("order_id"))
+			writeMockResponse(w, `null`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	var mu sync.Mutex
+	var updates int
+	execution, err := NewExecutor(client.Exchange(), nil).TWAP(context.Background(), ParentOrder{
+		Market:     OrderMarketSpot,
+		Symbol:     "BTCUSDT",
+		Side:       OrderSideBuy,
+		Amount:     decimal.NewFromInt(1),
+		LimitPrice: decimal.RequireFromString("100.37"),
+	}, TWAPConfig{
+		ExecutionOptions: ExecutionOptions{
+			PollInterval: 5 * time.Millisecond,
+			OnProgress: func(ExecutionProgress) {
+				mu.Lock()
+				updates++
+				mu.Unlock()
+			},
+		},
+		Duration: 60 * time.Millisecond,
+		Slices:   3,
+	})
+	if err != nil {
+		t.Fatalf("TWAP() returned error: %v", err)
+	}
+	if err := execution.Wait(); err != nil {
+		t.Fatalf("Wait() returned error: %v", err)
+	}
+
+	progress := execution.Progress()
+	if progress.State != ExecutionCompleted {
+		t.Errorf("Expected completed, got %s", progress.State)
+	}
+	if !progress.FilledAmount.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected filled amount 1, got %s", progress.FilledAmount)
+	}
+
+	expected := []string{"0.33", "0.45", "0.45"}
+	if len(venue.placed) != len(expected) {
+		t.Fatalf("Expected %d child orders, got %d", len(expected), len(venue.placed))
+	}
+	for i, want := range expected {
+		if !venue.placed[i].Amount.Equal(decimal.RequireFromString(want)) {
+			t.Errorf("Child %d: expected amount %s, got %s", i, want, venue.placed[i].Amount)
+		}
+		if !venue.placed[i].Price.Equal(decimal.RequireFromString("100.3")) {
+			t.Errorf("Child %d: expected price rounded down to 100.3, got %s", i, venue.placed[i].Price)
+		}
+	}
+	if len(venue.cancelled) != 1 || venue.cancelled[0] != "1" {
+		t.Errorf("Expected the partially filled slice to be cancelled, got %v", venue.cancelled)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if updates == 0 {
+		t.Error("Expected progress updates")
+	}
+}
+
+func TestExecutor_IcebergPauseAndCancel(t *testing.T) {
+	venue := &mockVenue{
+		orders: make(map[string]*ExchangeOrder),
+		fill: func(n int, amount decimal.Decimal) (decimal.Decimal, string) {
+			// The first child fills immediately, later children rest on the book
+			if n == 0 {
+				return amount, "FILLED"
+			}
+			return decimal.Zero, "NEW"
+		},
+	}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/trade/order":
+			if r.Method == "GET" {
+				writeMockResponse(w, venue.futuresInfo(r.URL.Query().Get("orderId")))
+				return
+			}
+			var body FuturesCreateOrderRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			id := venue.create(body.Volume, body.Price)
+			writeMockResponse(w, `{"orderId":"`+id+`"}`)
+		case "/fapi/v1/trade/cancel":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			venue.cancel(body["orderId"])
+			writeMockResponse(w, `null`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	// The callback can run before Iceberg returns, so the execution is handed over
+	started := make(chan *Execution, 1)
+	paused := make(chan struct{})
+	var once sync.Once
+	execution, err := NewExecutor(nil, client.Futures()).Iceberg(context.Background(), ParentOrder{
+		Market:     OrderMarketFutures,
+		Symbol:     "E-BTC-USDT",
+		Side:       OrderSideSell,
+		Amount:     decimal.NewFromInt(3),
+		LimitPrice: decimal.NewFromInt(50000),
+	}, IcebergConfig{
+		ExecutionOptions: ExecutionOptions{
+			Rules:        &SymbolCharge{StepSize: decimal.NewFromInt(1), TickSize: decimal.RequireFromString("0.5")},
+			PollInterval: 5 * time.Millisecond,
+			OnProgress: func(p ExecutionProgress) {
+				if p.FilledAmount.Equal(decimal.NewFromInt(1)) {
+					once.Do(func() {
+						(<-started).Pause()
+						close(paused)
+					})
+				}
+			},
+		},
+		DisplayAmount: decimal.NewFromInt(1),
+	})
+	if err != nil {
+		t.Fatalf("Iceberg() returned error: %v", err)
+	}
+	started <- execution
+
+	<-paused
+	time.Sleep(30 * time.Millisecond)
+	if n := venue.count(); n != 1 {
+		t.Fatalf("Expected no new child orders while paused, got %d", n)
+	}
+	if state := execution.Progress().State; state != ExecutionPaused {
+		t.Errorf("Expected paused, got %s", state)
+	}
+
+	execution.Resume()
+	for venue.count() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	execution.Cancel()
+	if err := execution.Wait(); err != nil {
+		t.Fatalf("Wait() returned error: %v", err)
+	}
+
+	progress := execution.Progress()
+	if progress.State != ExecutionCancelled {
+		t.Errorf("Expected cancelled, got %s", progress.State)
+	}
+	if !progress.FilledAmount.Equal(decimal.NewFromInt(1)) || !progress.Remaining().Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected 1 filled and 2 remaining, got %s and %s", progress.FilledAmount, progress.Remaining())
+	}
+	if len(venue.cancelled) != 1 || venue.cancelled[0] != "2" {
+		t.Errorf("Expected the resting child to be cancelled, got %v", venue.cancelled)
+	}
+}
+
+func TestExecutor_IcebergRemainder(t *testing.T) {
+	venue := &mockVenue{
+		orders: make(map[string]*ExchangeOrder),
+		fill: func(n int, amount decimal.Decimal) (decimal.Decimal, string) {
+			return amount, "FILLED"
+		},
+	}
+
+	var infoCalls int
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/create_order":
+			r.ParseForm()
+			id := venue.create(decimal.RequireFromString(r.PostForm.Get("volume")), decimal.RequireFromString(r.PostForm.Get("price")))
+			writeMockResponse(w, `{"orderId":"`+id+`"}`)
+		case "/open/api/order_info":
+			// The first poll fails transiently
+			infoCalls++
+			if infoCalls == 1 {
+				fmt.Fprint(w, `{"code":"500","msg":"busy"}`)
+				return
+			}
+			writeMockResponse(w, venue.info(r.URL.Query().Get("order_id")))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	execution, err := NewExecutor(client.Exchange(), nil).Iceberg(context.Background(), ParentOrder{
+		Market:     OrderMarketSpot,
+		Symbol:     "BTCUSDT",
+		Side:       OrderSideBuy,
+		Amount:     decimal.RequireFromString("1.005"),
+		LimitPrice: decimal.NewFromInt(100),
+	}, IcebergConfig{
+		ExecutionOptions: ExecutionOptions{
+			Rules:        &SymbolCharge{StepSize: decimal.RequireFromString("0.01"), MinQty: decimal.RequireFromString("0.01")},
+			PollInterval: 5 * time.Millisecond,
+		},
+		DisplayAmount: decimal.RequireFromString("0.5"),
+	})
+	if err != nil {
+		t.Fatalf("Iceberg() returned error: %v", err)
+	}
+
+	if err := execution.Wait(); !errors.Is(err, ErrExecutionIncomplete) {
+		t.Errorf("Expected ErrExecutionIncomplete, got %v", err)
+	}
+
+	progress := execution.Progress()
+	if progress.State != ExecutionFailed {
+		t.Errorf("Expected failed, got %s", progress.State)
+	}
+	if !progress.Remaining().Equal(decimal.RequireFromString("0.005")) {
+		t.Errorf("Expected remaining 0.005, got %s", progress.Remaining())
+	}
+	if n := venue.count(); n != 2 {
+		t.Errorf("Expected 2 child orders, got %d", n)
+	}
+}
@@ -0,0 +1,247 @@
+package byex
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExportFormat selects the file format of a RecordWriter
+type ExportFormat string
+
+const (
+	// ExportFormatCSV writes a header row followed by one row per record
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatJSONL writes one JSON object per line
+	ExportFormatJSONL ExportFormat = "jsonl"
+)
+
+// exportColumn is one field of an exported record
+type exportColumn[T any] struct {
+	name  string
+	value func(T) string
+	// number is written to JSONL unquoted
+	number bool
+}
+
+// RecordWriter writes records of one type as CSV or JSONL with a fixed column order.
+// Decimals keep their exact value but not their formatting: they are written without an
+// exponent and trailing zeros are dropped, so "48000.50" becomes "48000.5". Every timestamp is
+// written twice: as the epoch value the API returned and, in a column with an _rfc3339 suffix,
+// as an RFC3339 time.
+type RecordWriter[T any] struct {
+	w       io.Writer
+	format  ExportFormat
+	columns []exportColumn[T]
+	csv     *csv.Writer
+	started bool
+}
+
+func newRecordWriter[T any](w io.Writer, format ExportFormat, columns []exportColumn[T]) (*RecordWriter[T], error) {
+	rw := &RecordWriter[T]{w: w, format: format, columns: columns}
+	switch format {
+	case ExportFormatCSV:
+		rw.csv = csv.NewWriter(w)
+	case ExportFormatJSONL:
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	return rw, nil
+}
+
+// NewExchangeTradeWriter creates a writer for spot trades
+func NewExchangeTradeWriter(w io.Writer, format ExportFormat) (*RecordWriter[ExchangeTrade], error) {
+	return newRecordWriter(w, format, withTime([]exportColumn[ExchangeTrade]{
+		textColumn("id", func(t ExchangeTrade) string { return t.ID }),
+		textColumn("order_id", func(t ExchangeTrade) string { return t.OrderID }),
+		textColumn("symbol", func(t ExchangeTrade) string { return t.Symbol }),
+		textColumn("side", func(t ExchangeTrade) string { return t.Side.String() }),
+		decimalColumn("amount", func(t ExchangeTrade) decimal.Decimal { return t.Amount }),
+		decimalColumn("price", func(t ExchangeTrade) decimal.Decimal { return t.Price }),
+		decimalColumn("fee", func(t ExchangeTrade) decimal.Decimal { return t.Fee }),
+		textColumn("fee_currency", func(t ExchangeTrade) string { return t.FeeCurrency }),
+		textColumn("role", func(t ExchangeTrade) string { return t.Role }),
+	}, "created_at", func(t ExchangeTrade) Timestamp { return t.CreatedAt }))
+}
+
+// NewFuturesTradeWriter creates a writer for futures trades
+func NewFuturesTradeWriter(w io.Writer, format ExportFormat) (*RecordWriter[FuturesTrade], error) {
+	return newRecordWriter(w, format, withTime([]exportColumn[FuturesTrade]{
+		textColumn("id", func(t FuturesTrade) string { return t.ID }),
+		textColumn("order_id", func(t FuturesTrade) string { return t.OrderID }),
+		textColumn("symbol", func(t FuturesTrade) string { return t.Symbol }),
+		textColumn("side", func(t FuturesTrade) string { return t.Side.String() }),
+		decimalColumn("volume", func(t FuturesTrade) decimal.Decimal { return t.Volume }),
+		decimalColumn("price", func(t FuturesTrade) decimal.Decimal { return t.Price }),
+		decimalColumn("fee", func(t FuturesTrade) decimal.Decimal { return t.Fee }),
+	}, "timestamp", func(t FuturesTrade) Timestamp { return t.Timestamp }))
+}
+
+// NewExchangeOrderWriter creates a writer for spot orders
+func NewExchangeOrderWriter(w io.Writer, format ExportFormat) (*RecordWriter[ExchangeOrder], error) {
+	columns := []exportColumn[ExchangeOrder]{
+		textColumn("id", func(o ExchangeOrder) string { return o.ID }),
+		textColumn("client_order_id", func(o ExchangeOrder) string { return o.ClientOrderID }),
+		textColumn("symbol", func(o ExchangeOrder) string { return o.Symbol }),
+		textColumn("type", func(o ExchangeOrder) string { return o.Type.String() }),
+		textColumn("side", func(o ExchangeOrder) string { return o.Side.String() }),
+		decimalColumn("amount", func(o ExchangeOrder) decimal.Decimal { return o.Amount }),
+		decimalColumn("price", func(o ExchangeOrder) decimal.Decimal { return o.Price }),
+		textColumn("status", func(o ExchangeOrder) string { return o.Status.String() }),
+		decimalColumn("avg_price", func(o ExchangeOrder) decimal.Decimal { return o.AvgPrice }),
+		decimalColumn("filled_amount", func(o ExchangeOrder) decimal.Decimal { return o.FilledAmount }),
+		decimalColumn("filled_cash_amount", func(o ExchangeOrder) decimal.Decimal { return o.FilledCashAmount }),
+		decimalColumn("filled_fees", func(o ExchangeOrder) decimal.Decimal { return o.FilledFees }),
+		decimalColumn("fee", func(o ExchangeOrder) decimal.Decimal { return o.Fee }),
+		textColumn("fee_currency", func(o ExchangeOrder) string { return o.FeeCurrency }),
+		textColumn("source", func(o ExchangeOrder) string { return o.Source }),
+	}
+	columns = withTime(columns, "created_at", func(o ExchangeOrder) Timestamp { return o.CreatedAt })
+	columns = withTime(columns, "updated_at", func(o ExchangeOrder) Timestamp { return o.UpdatedAt })
+	columns = withTime(columns, "finished_at", func(o ExchangeOrder) Timestamp { return o.FinishedAt })
+	columns = withTime(columns, "cancelled_at", func(o ExchangeOrder) Timestamp { return o.CancelledAt })
+
+	return newRecordWriter(w, format, columns)
+}
+
+// NewFuturesOrderWriter creates a writer for futures orders
+func NewFuturesOrderWriter(w io.Writer, format ExportFormat) (*RecordWriter[FuturesOrder], error) {
+	columns := []exportColumn[FuturesOrder]{
+		textColumn("order_id", func(o FuturesOrder) string { return o.OrderID }),
+		textColumn("client_order_id", func(o FuturesOrder) string { return o.ClientOrderID }),
+		textColumn("symbol", func(o FuturesOrder) string { return o.Symbol }),
+		textColumn("type", func(o FuturesOrder) string { return o.Type.String() }),
+		textColumn("side", func(o FuturesOrder) string { return o.Side.String() }),
+		textColumn("open", func(o FuturesOrder) string { return o.Open }),
+		textColumn("position_type", func(o FuturesOrder) string { return o.PositionType.String() }),
+		decimalColumn("price", func(o FuturesOrder) decimal.Decimal { return o.Price }),
+		decimalColumn("volume", func(o FuturesOrder) decimal.Decimal { return o.Volume }),
+		decimalColumn("executed_qty", func(o FuturesOrder) decimal.Decimal { return o.ExecutedQty }),
+		decimalColumn("avg_price", func(o FuturesOrder) decimal.Decimal { return o.AvgPrice }),
+		textColumn("status", func(o FuturesOrder) string { return o.Status.String() }),
+	}
+	columns = withTime(columns, "created_at", func(o FuturesOrder) Timestamp { return o.CreatedAt })
+	columns = withTime(columns, "updated_at", func(o FuturesOrder) Timestamp { return o.UpdatedAt })
+
+	return newRecordWriter(w, format, columns)
+}
+
+// NewKlineWriter creates a writer for klines
+func NewKlineWriter(w io.Writer, format ExportFormat) (*RecordWriter[ExchangeKline], error) {
+	return newRecordWriter(w, format, append(withTime(nil, "time", func(k ExchangeKline) Timestamp { return k.Time }),
+		decimalColumn("open", func(k ExchangeKline) decimal.Decimal { return k.Open }),
+		decimalColumn("high", func(k ExchangeKline) decimal.Decimal { return k.High }),
+		decimalColumn("low", func(k ExchangeKline) decimal.Decimal { return k.Low }),
+		decimalColumn("close", func(k ExchangeKline) decimal.Decimal { return k.Close }),
+		decimalColumn("volume", func(k ExchangeKline) decimal.Decimal { return k.Volume }),
+	))
+}
+
+// Columns returns the column names in the order they are written
+func (rw *RecordWriter[T]) Columns() []string {
+	names := make([]string, 0, len(rw.columns))
+	for _, c := range rw.columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// Write writes one record. The CSV header is written before the first record.
+func (rw *RecordWriter[T]) Write(record T) error {
+	if rw.format == ExportFormatJSONL {
+		return rw.writeJSONL(record)
+	}
+
+	if !rw.started {
+		rw.started = true
+		if err := rw.csv.Write(rw.Columns()); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, 0, len(rw.columns))
+	for _, c := range rw.columns {
+		row = append(row, c.value(record))
+	}
+	return rw.csv.Write(row)
+}
+
+// WriteAll writes records and flushes the writer. A CSV export of no records still gets its header.
+func (rw *RecordWriter[T]) WriteAll(records []T) error {
+	for _, record := range records {
+		if err := rw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	if rw.format == ExportFormatCSV && !rw.started {
+		rw.started = true
+		if err := rw.csv.Write(rw.Columns()); err != nil {
+			return err
+		}
+	}
+	return rw.Flush()
+}
+
+// Flush writes any buffered CSV data to the underlying writer
+func (rw *RecordWriter[T]) Flush() error {
+	if rw.csv == nil {
+		return nil
+	}
+
+	rw.csv.Flush()
+	return rw.csv.Error()
+}
+
+// writeJSONL writes record as one JSON object with the keys in column order
+func (rw *RecordWriter[T]) writeJSONL(record T) error {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range rw.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(c.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+
+		value := c.value(record)
+		if c.number {
+			buf.WriteString(value)
+			continue
+		}
+		quoted, _ := json.Marshal(value)
+		buf.Write(quoted)
+	}
+	buf.WriteString("}\n")
+
+	_, err := rw.w.Write(buf.Bytes())
+	return err
+}
+
+func textColumn[T any](name string, value func(T) string) exportColumn[T] {
+	return exportColumn[T]{name: name, value: value}
+}
+
+// decimalColumn writes the decimal as a string so no precision is lost to float parsing
+func decimalColumn[T any](name string, value func(T) decimal.Decimal) exportColumn[T] {
+	return exportColumn[T]{name: name, value: func(record T) string { return value(record).String() }}
+}
+
+// withTime appends the epoch column name and its RFC3339 column name_rfc3339
+func withTime[T any](columns []exportColumn[T], name string, value func(T) Timestamp) []exportColumn[T] {
+	return append(columns,
+		exportColumn[T]{name: name, number: true, value: func(record T) string {
+			return strconv.FormatInt(value(record).Int64(), 10)
+		}},
+		exportColumn[T]{name: name + "_rfc3339", value: func(record T) string {
+			return value(record).String()
+		}},
+	)
+}
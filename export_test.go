@@ -0,0 +1,82 @@
+package byex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRecordWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewExchangeTradeWriter(&buf, ExportFormatCSV)
+	if err != nil {
+		t.Fatalf("NewExchangeTradeWriter() returned error: %v", err)
+	}
+
+	trades := []ExchangeTrade{{
+		ID:          "1",
+		OrderID:     "9",
+		Symbol:      "btcusdt",
+		Side:        "BUY",
+		Amount:      decimal.RequireFromString("0.123456789012345678"),
+		Price:       decimal.RequireFromString("50000.10"),
+		Fee:         decimal.RequireFromString("0.0001"),
+		FeeCurrency: "btc",
+		Role:        "taker",
+		CreatedAt:   NewTimestamp(1767225600123),
+	}}
+	if err := rw.WriteAll(trades); err != nil {
+		t.Fatalf("WriteAll() returned error: %v", err)
+	}
+
+	expected := "id,order_id,symbol,side,amount,price,fee,fee_currency,role,created_at,created_at_rfc3339\n" +
+		"1,9,btcusdt,BUY,0.123456789012345678,50000.1,0.0001,btc,taker,1767225600123,2026-01-01T00:00:00.123Z\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestRecordWriter_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewKlineWriter(&buf, ExportFormatJSONL)
+	if err != nil {
+		t.Fatalf("NewKlineWriter() returned error: %v", err)
+	}
+
+	klines := []ExchangeKline{
+		{Time: NewTimestamp(1767225600), Open: decimal.RequireFromString("1.10"), High: decimal.NewFromInt(2), Low: decimal.NewFromInt(1), Close: decimal.NewFromInt(2), Volume: decimal.RequireFromString("10.5")},
+		{Time: NewTimestamp(1767225660)},
+	}
+	if err := rw.WriteAll(klines); err != nil {
+		t.Fatalf("WriteAll() returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	expected := `{"time":1767225600,"time_rfc3339":"2026-01-01T00:00:00.000Z","open":"1.1","high":"2","low":"1","close":"2","volume":"10.5"}`
+	if lines[0] != expected {
+		t.Errorf("Expected %s, got %s", expected, lines[0])
+	}
+}
+
+func TestRecordWriter_EmptyAndUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewFuturesOrderWriter(&buf, ExportFormatCSV)
+	if err != nil {
+		t.Fatalf("NewFuturesOrderWriter() returned error: %v", err)
+	}
+	if err := rw.WriteAll(nil); err != nil {
+		t.Fatalf("WriteAll() returned error: %v", err)
+	}
+	if header := strings.Join(rw.Columns(), ",") + "\n"; buf.String() != header {
+		t.Errorf("Expected only the header %q, got %q", header, buf.String())
+	}
+
+	if _, err := NewFuturesTradeWriter(&buf, "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
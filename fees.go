@@ -0,0 +1,178 @@
+package byex
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// FeeRoleTaker is the role of a fill that took liquidity
+	FeeRoleTaker = "taker"
+	// FeeRoleMaker is the role of a fill that added liquidity
+	FeeRoleMaker = "maker"
+)
+
+// defaultFeeTolerance is the relative rate difference accepted when none is configured
+var defaultFeeTolerance = decimal.RequireFromString("0.01")
+
+// FeeReportOptions configures BuildFeeReport
+type FeeReportOptions struct {
+	// Start and End limit the report to fills in [Start, End). Zero leaves that side open.
+	Start time.Time
+	End   time.Time
+	// Tolerance is the relative difference between the charged and the configured rate
+	// that is not flagged. Zero uses 1%, which absorbs the exchange's fee rounding.
+	Tolerance decimal.Decimal
+}
+
+// FeeGroup aggregates the fees of one symbol, role and fee currency
+type FeeGroup struct {
+	Symbol      string
+	Role        string
+	FeeCurrency string
+	Fills       int
+	// Notional and FeesInQuote are in the quote currency of the symbol
+	Notional    decimal.Decimal
+	Fees        decimal.Decimal
+	FeesInQuote decimal.Decimal
+	// EffectiveRate is FeesInQuote / Notional, and ExpectedRate the configured commission.
+	// ExpectedRate is zero when the symbol or role has no configured rate.
+	EffectiveRate decimal.Decimal
+	ExpectedRate  decimal.Decimal
+}
+
+// FeeDiscrepancy is a fill that was not charged as configured
+type FeeDiscrepancy struct {
+	Fill         Fill
+	ExpectedRate decimal.Decimal
+	ChargedRate  decimal.Decimal
+	Reason       string
+}
+
+// FeeReport is the result of BuildFeeReport
+type FeeReport struct {
+	Groups        []FeeGroup
+	Discrepancies []FeeDiscrepancy
+}
+
+// BuildFeeReport aggregates the fees of fills by symbol, role and fee currency, and flags
+// fills whose charged rate differs from the TakerCommission or MakerCommission of their
+// symbol in charges (e.g. from GetSymbolsCharge). Commissions are fractions of the notional.
+// prices converts fees charged in a third currency, as in CalculatePnL.
+// Fills without a role, such as futures fills, are aggregated but not checked.
+func BuildFeeReport(fills []Fill, charges []SymbolCharge, prices map[string]decimal.Decimal, opts FeeReportOptions) *FeeReport {
+	tolerance := opts.Tolerance
+	if !tolerance.IsPositive() {
+		tolerance = defaultFeeTolerance
+	}
+	prices = normalizePrices(prices)
+
+	rates := make(map[string]SymbolCharge, len(charges))
+	for _, charge := range charges {
+		rates[strings.ToUpper(charge.Symbol)] = charge
+	}
+
+	type groupKey struct{ symbol, role, currency string }
+	groups := make(map[groupKey]*FeeGroup)
+	report := &FeeReport{}
+
+	for _, fill := range fills {
+		if !opts.Start.IsZero() && fill.Time.Before(opts.Start) || !opts.End.IsZero() && !fill.Time.Before(opts.End) {
+			continue
+		}
+
+		symbol := strings.ToUpper(fill.Symbol)
+		role := strings.ToLower(fill.Role)
+		key := groupKey{symbol, role, strings.ToUpper(fill.FeeCurrency)}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &FeeGroup{Symbol: key.symbol, Role: key.role, FeeCurrency: key.currency}
+			if charge, ok := rates[symbol]; ok {
+				group.ExpectedRate = commissionFor(charge, role)
+			}
+			groups[key] = group
+		}
+
+		notional := fill.Amount.Mul(fill.Price)
+		fee, converted := feeInQuote(fill, prices)
+
+		group.Fills++
+		group.Notional = group.Notional.Add(notional)
+		group.Fees = group.Fees.Add(fill.Fee)
+		group.FeesInQuote = group.FeesInQuote.Add(fee)
+
+		if role == "" {
+			continue
+		}
+		if discrepancy, ok := checkFee(fill, rates, role, notional, fee, converted, tolerance); !ok {
+			report.Discrepancies = append(report.Discrepancies, discrepancy)
+		}
+	}
+
+	for _, group := range groups {
+		if group.Notional.IsPositive() {
+			group.EffectiveRate = group.FeesInQuote.Div(group.Notional)
+		}
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		return a.FeeCurrency < b.FeeCurrency
+	})
+
+	return report
+}
+
+// checkFee compares the rate charged on fill with the configured commission
+func checkFee(fill Fill, rates map[string]SymbolCharge, role string, notional, fee decimal.Decimal, converted bool, tolerance decimal.Decimal) (FeeDiscrepancy, bool) {
+	d := FeeDiscrepancy{Fill: fill}
+
+	charge, ok := rates[strings.ToUpper(fill.Symbol)]
+	if !ok {
+		d.Reason = "no commission configured for symbol"
+		return d, false
+	}
+	if role != FeeRoleTaker && role != FeeRoleMaker {
+		d.Reason = "unknown role " + role
+		return d, false
+	}
+	d.ExpectedRate = commissionFor(charge, role)
+
+	if !converted {
+		d.Reason = "fee currency " + fill.FeeCurrency + " could not be converted"
+		return d, false
+	}
+	if !notional.IsPositive() {
+		return d, true
+	}
+
+	d.ChargedRate = fee.Div(notional)
+	if d.ChargedRate.Sub(d.ExpectedRate).Abs().GreaterThan(d.ExpectedRate.Mul(tolerance)) {
+		d.Reason = "charged rate differs from the configured " + role + " commission"
+		return d, false
+	}
+
+	return d, true
+}
+
+// commissionFor returns the commission of role configured in charge
+func commissionFor(charge SymbolCharge, role string) decimal.Decimal {
+	switch role {
+	case FeeRoleTaker:
+		return charge.TakerCommission
+	case FeeRoleMaker:
+		return charge.MakerCommission
+	default:
+		return decimal.Zero
+	}
+}
@@ -0,0 +1,52 @@
+package byex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildFeeReport(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := decimal.RequireFromString
+
+	charges := []SymbolCharge{{Symbol: "btcusdt", TakerCommission: d("0.001"), MakerCommission: d("0.0005")}}
+	fills := []Fill{
+		// Charged as configured; the first one in the base asset
+		{ID: "1", Symbol: "BTCUSDT", Role: FeeRoleTaker, Amount: d("1"), Price: d("100"), Fee: d("0.001"), FeeCurrency: "BTC", Time: start},
+		{ID: "2", Symbol: "BTCUSDT", Role: FeeRoleMaker, Amount: d("1"), Price: d("200"), Fee: d("0.1"), FeeCurrency: "USDT", Time: start.Add(time.Hour)},
+		// Charged twice the taker rate
+		{ID: "3", Symbol: "BTCUSDT", Role: FeeRoleTaker, Amount: d("2"), Price: d("100"), Fee: d("0.4"), FeeCurrency: "USDT", Time: start.Add(2 * time.Hour)},
+		// No configured rate
+		{ID: "4", Symbol: "ETHUSDT", Role: FeeRoleTaker, Amount: d("1"), Price: d("10"), Fee: d("0.01"), FeeCurrency: "USDT", Time: start.Add(3 * time.Hour)},
+		// Outside the range
+		{ID: "5", Symbol: "BTCUSDT", Role: FeeRoleTaker, Amount: d("1"), Price: d("100"), Fee: d("1"), FeeCurrency: "USDT", Time: start.Add(48 * time.Hour)},
+	}
+
+	report := BuildFeeReport(fills, charges, nil, FeeReportOptions{Start: start, End: start.Add(24 * time.Hour)})
+
+	if len(report.Groups) != 4 {
+		t.Fatalf("Expected 4 groups, got %+v", report.Groups)
+	}
+	taker := report.Groups[2]
+	if taker.Symbol != "BTCUSDT" || taker.Role != FeeRoleTaker || taker.FeeCurrency != "USDT" {
+		t.Fatalf("Unexpected group order: %+v", report.Groups)
+	}
+	if taker.Fills != 1 || !taker.EffectiveRate.Equal(d("0.002")) || !taker.ExpectedRate.Equal(d("0.001")) {
+		t.Errorf("Unexpected taker group %+v", taker)
+	}
+	if !report.Groups[1].FeesInQuote.Equal(d("0.1")) {
+		t.Errorf("Expected the BTC fee converted at the fill price, got %+v", report.Groups[1])
+	}
+
+	if len(report.Discrepancies) != 2 {
+		t.Fatalf("Expected 2 discrepancies, got %+v", report.Discrepancies)
+	}
+	if report.Discrepancies[0].Fill.ID != "3" || !report.Discrepancies[0].ChargedRate.Equal(d("0.002")) {
+		t.Errorf("Expected fill 3 to be flagged, got %+v", report.Discrepancies[0])
+	}
+	if report.Discrepancies[1].Fill.ID != "4" || report.Discrepancies[1].Reason == "" {
+		t.Errorf("Expected fill 4 to be flagged for the missing rate, got %+v", report.Discrepancies[1])
+	}
+}
@@ -0,0 +1,224 @@
+package byex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const defaultFillsPageSize = 100
+
+// spotQuoteAssets are matched as suffixes to split spot symbols, longest names first
+var spotQuoteAssets = []string{"USDT", "USDC", "BUSD", "BTC", "ETH"}
+
+// Fill is a spot or futures trade in one shape for PnL, fee and history tooling
+type Fill struct {
+	Market  OrderMarket     `json:"market"`
+	ID      string          `json:"id"`
+	OrderID string          `json:"orderId"`
+	Symbol  string          `json:"symbol"`
+	Side    OrderSide       `json:"side"`
+	Amount  decimal.Decimal `json:"amount"`
+	Price   decimal.Decimal `json:"price"`
+	Fee     decimal.Decimal `json:"fee"`
+	// FeeCurrency is empty when the fee is charged in the quote currency
+	FeeCurrency string    `json:"feeCurrency,omitempty"`
+	Role        string    `json:"role,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// FillFromExchangeTrade converts a spot trade
+func FillFromExchangeTrade(trade ExchangeTrade) Fill {
+	return Fill{
+		Market:      OrderMarketSpot,
+		ID:          trade.ID,
+		OrderID:     trade.OrderID,
+		Symbol:      strings.ToUpper(trade.Symbol),
+		Side:        OrderSide(trade.Side.String()),
+		Amount:      trade.Amount,
+		Price:       trade.Price,
+		Fee:         trade.Fee,
+		FeeCurrency: strings.ToUpper(trade.FeeCurrency),
+		Role:        strings.ToLower(trade.Role),
+		Time:        trade.CreatedAt.Time(),
+	}
+}
+
+// FillFromFuturesTrade converts a futures trade. Futures fees are charged in the settlement asset.
+func FillFromFuturesTrade(trade FuturesTrade) Fill {
+	return Fill{
+		Market:  OrderMarketFutures,
+		ID:      trade.ID,
+		OrderID: trade.OrderID,
+		Symbol:  strings.ToUpper(trade.Symbol),
+		Side:    OrderSide(trade.Side.String()),
+		Amount:  trade.Volume,
+		Price:   trade.Price,
+		Fee:     trade.Fee,
+		Time:    trade.Timestamp.Time(),
+	}
+}
+
+// LoadSpotFills pages through the whole spot trade history of symbol with GetAllTradingRecords
+func LoadSpotFills(exchange *ExchangeAPI, symbol string) ([]Fill, error) {
+	trades, err := LoadSpotTrades(exchange, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	fills := make([]Fill, 0, len(trades))
+	for _, trade := range trades {
+		fills = append(fills, FillFromExchangeTrade(trade))
+	}
+
+	sortFills(fills)
+	return fills, nil
+}
+
+// LoadFuturesFills pages through the futures trade history of futuresName with GetMyTrades.
+// fromID continues after a trade already loaded; empty starts at the oldest trade.
+func LoadFuturesFills(futures *FuturesAPI, futuresName, fromID string) ([]Fill, error) {
+	trades, err := LoadFuturesTrades(futures, futuresName, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	fills := make([]Fill, 0, len(trades))
+	for _, trade := range trades {
+		fills = append(fills, FillFromFuturesTrade(trade))
+	}
+
+	sortFills(fills)
+	return fills, nil
+}
+
+// LoadSpotTrades pages through the whole spot trade history of symbol with GetAllTradingRecords.
+// Trades without a symbol get symbol.
+func LoadSpotTrades(exchange *ExchangeAPI, symbol string) ([]ExchangeTrade, error) {
+	var trades []ExchangeTrade
+	seen := newStringSet()
+	for page := 1; ; page++ {
+		resp, err := exchange.GetAllTradingRecords(symbol, defaultFillsPageSize, page, 0, "", "", 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trades for %s: %w", symbol, err)
+		}
+
+		added := 0
+		for _, trade := range resp.ResultList {
+			if _, ok := seen[trade.ID]; ok {
+				continue
+			}
+			seen.add(trade.ID)
+			if trade.Symbol == "" {
+				trade.Symbol = symbol
+			}
+			trades = append(trades, trade)
+			added++
+		}
+
+		// count may be missing, so only trust it when it is set. A page without new
+		// trades means the server ignores page and would repeat it forever.
+		if len(resp.ResultList) < defaultFillsPageSize || resp.Count > 0 && len(trades) >= resp.Count || added == 0 {
+			return trades, nil
+		}
+	}
+}
+
+// LoadFuturesTrades pages through the futures trade history of futuresName with GetMyTrades.
+// fromID continues after a trade already loaded; empty starts at the oldest trade.
+// Trades without a symbol get futuresName.
+func LoadFuturesTrades(futures *FuturesAPI, futuresName, fromID string) ([]FuturesTrade, error) {
+	var result []FuturesTrade
+	seen := newStringSet(fromID)
+	for {
+		trades, err := futures.GetMyTrades(futuresName, fromID, defaultFillsPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trades for %s: %w", futuresName, err)
+		}
+
+		added := 0
+		for _, trade := range trades {
+			// fromId is inclusive, so the trade it points at comes back again
+			if _, ok := seen[trade.ID]; ok {
+				continue
+			}
+			seen.add(trade.ID)
+			if trade.Symbol == "" {
+				trade.Symbol = futuresName
+			}
+			result = append(result, trade)
+			fromID = trade.ID
+			added++
+		}
+
+		if len(trades) < defaultFillsPageSize || added == 0 {
+			return result, nil
+		}
+	}
+}
+
+// LoadSpotOrders pages through the whole spot order history of symbol with GetOrderHistory.
+// Orders without a symbol get symbol.
+func LoadSpotOrders(exchange *ExchangeAPI, symbol string) ([]ExchangeOrder, error) {
+	var orders []ExchangeOrder
+	seen := newStringSet()
+	for page := 1; ; page++ {
+		resp, err := exchange.GetOrderHistory(symbol, defaultFillsPageSize, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order history for %s: %w", symbol, err)
+		}
+
+		added := 0
+		for _, order := range resp.ResultList {
+			if _, ok := seen[order.ID]; ok {
+				continue
+			}
+			seen.add(order.ID)
+			if order.Symbol == "" {
+				order.Symbol = symbol
+			}
+			orders = append(orders, order)
+			added++
+		}
+
+		// count may be missing, so only trust it when it is set. A page without new
+		// orders means the server ignores page and would repeat it forever.
+		if len(resp.ResultList) < defaultFillsPageSize || resp.Count > 0 && len(orders) >= resp.Count || added == 0 {
+			return orders, nil
+		}
+	}
+}
+
+// sortFills orders fills by time, breaking ties by ID
+func sortFills(fills []Fill) {
+	sort.SliceStable(fills, func(i, j int) bool {
+		if !fills[i].Time.Equal(fills[j].Time) {
+			return fills[i].Time.Before(fills[j].Time)
+		}
+		return fills[i].ID < fills[j].ID
+	})
+}
+
+// splitSymbol returns the base and quote assets of a spot symbol like BTCUSDT
+// or a futures name like E-BTC-USDT. It reports false when they cannot be told apart.
+func splitSymbol(market OrderMarket, symbol string) (string, string, bool) {
+	symbol = strings.ToUpper(symbol)
+
+	if market == OrderMarketFutures || strings.Contains(symbol, "-") {
+		parts := strings.Split(symbol, "-")
+		if len(parts) < 2 {
+			return "", "", false
+		}
+		return parts[len(parts)-2], parts[len(parts)-1], true
+	}
+
+	for _, quote := range spotQuoteAssets {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base, quote, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,106 @@
+package byex
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSplitSymbol(t *testing.T) {
+	tests := []struct {
+		market      OrderMarket
+		symbol      string
+		base, quote string
+		ok          bool
+	}{
+		{OrderMarketSpot, "btcusdt", "BTC", "USDT", true},
+		{OrderMarketSpot, "ETHBTC", "ETH", "BTC", true},
+		{OrderMarketFutures, "E-BTC-USDT", "BTC", "USDT", true},
+		{OrderMarketSpot, "USDT", "", "", false},
+		{OrderMarketFutures, "BTCUSDT", "", "", false},
+	}
+
+	for _, tt := range tests {
+		base, quote, ok := splitSymbol(tt.market, tt.symbol)
+		if base != tt.base || quote != tt.quote || ok != tt.ok {
+			t.Errorf("%s: expected %s/%s %v, got %s/%s %v", tt.symbol, tt.base, tt.quote, tt.ok, base, quote, ok)
+		}
+	}
+}
+
+func TestLoadFills(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/all_trade":
+			writeMockResponse(w, `{"count":2,"resultList":[
+				{"id":"2","symbol":"btcusdt","side":"SELL","amount":"1","price":"200","fee":"0.2","fee_currency":"usdt","role":"MAKER","created_at":1767225600000},
+				{"id":"1","symbol":"btcusdt","side":"BUY","amount":"1","price":"100","fee":"0.001","fee_currency":"btc","role":"TAKER","created_at":1767222000000}
+			]}`)
+		case "/fapi/v1/userTrades":
+			if r.URL.Query().Get("fromId") != "5" {
+				t.Errorf("Expected fromId 5, got %v", r.URL.Query())
+			}
+			writeMockResponse(w, `[
+				{"id":"5","side":"BUY","volume":"1","price":"100","timestamp":1767222000000},
+				{"id":"6","side":"SELL","volume":"1","price":"110","fee":"0.1","timestamp":1767225600000}
+			]`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	spot, err := LoadSpotFills(client.Exchange(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("LoadSpotFills() returned error: %v", err)
+	}
+	if len(spot) != 2 || spot[0].ID != "1" {
+		t.Fatalf("Expected 2 fills in time order, got %+v", spot)
+	}
+	if spot[0].Symbol != "BTCUSDT" || spot[0].FeeCurrency != "BTC" || spot[0].Role != "taker" || !spot[0].Time.Equal(time.UnixMilli(1767222000000)) {
+		t.Errorf("Unexpected spot fill %+v", spot[0])
+	}
+
+	futures, err := LoadFuturesFills(client.Futures(), "E-BTC-USDT", "5")
+	if err != nil {
+		t.Fatalf("LoadFuturesFills() returned error: %v", err)
+	}
+	if len(futures) != 1 || futures[0].ID != "6" || futures[0].Symbol != "E-BTC-USDT" || !futures[0].Amount.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected only the trade after fromId, got %+v", futures)
+	}
+}
+
+func TestLoadSpotHistory_PageIgnored(t *testing.T) {
+	// A full page of records that comes back for every page number
+	var records []string
+	for i := 0; i < defaultFillsPageSize; i++ {
+		records = append(records, fmt.Sprintf(`{"id":"%d","symbol":"btcusdt"}`, i+1))
+	}
+	page := `{"count":0,"resultList":[` + strings.Join(records, ",") + `]}`
+
+	requests := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeMockResponse(w, page)
+	})
+
+	trades, err := LoadSpotTrades(client.Exchange(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("LoadSpotTrades() returned error: %v", err)
+	}
+	if len(trades) != defaultFillsPageSize || requests != 2 {
+		t.Errorf("Expected %d trades after a repeated page, got %d in %d requests", defaultFillsPageSize, len(trades), requests)
+	}
+
+	requests = 0
+	orders, err := LoadSpotOrders(client.Exchange(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("LoadSpotOrders() returned error: %v", err)
+	}
+	if len(orders) != defaultFillsPageSize || requests != 2 {
+		t.Errorf("Expected %d orders after a repeated page, got %d in %d requests", defaultFillsPageSize, len(orders), requests)
+	}
+}
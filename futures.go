@@ -1,9 +1,19 @@
 package byex
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrInvalidTriggerPrice is returned for a conditional order without a positive trigger price
+	ErrInvalidTriggerPrice = errors.New("invalid trigger price")
+	// ErrInvalidTriggerType is returned for a trigger type other than LAST, MARK or INDEX
+	ErrInvalidTriggerType = errors.New("invalid trigger type")
 )
 
 // FuturesAPI represents the futures API methods
@@ -29,13 +39,8 @@ func (f *FuturesAPI) GetTicker(symbol string) (*FuturesTicker, error) {
 		return nil, err
 	}
 
-	var result FuturesTicker
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[FuturesTicker](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse ticker response: %w", err)
 	}
 
@@ -57,13 +62,8 @@ func (f *FuturesAPI) GetDepth(symbol string, limit int) (*ExchangeDepth, error)
 		return nil, err
 	}
 
-	var result ExchangeDepth
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[ExchangeDepth](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse depth response: %w", err)
 	}
 
@@ -86,13 +86,8 @@ func (f *FuturesAPI) GetKlines(symbol, interval string, limit int) ([]ExchangeKl
 		return nil, err
 	}
 
-	var result []ExchangeKline
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]ExchangeKline](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse klines response: %w", err)
 	}
 
@@ -103,21 +98,43 @@ func (f *FuturesAPI) GetKlines(symbol, interval string, limit int) ([]ExchangeKl
 
 // CreateOrder creates a new futures order
 func (f *FuturesAPI) CreateOrder(req FuturesCreateOrderRequest) (*OrderResponse, error) {
-	resp, err := f.client.doFuturesRequest("POST", "/fapi/v1/trade/order", req)
+	if err := validateOrder(req.Side, req.Type); err != nil {
+		return nil, err
+	}
+	risk := futuresRiskOrder(req.FuturesName, req.Side, req.Type, req.Open, req.Price, req.Volume)
+
+	clientOrderID, err := f.client.nextClientOrderID(req.ClientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	req.ClientOrderID = clientOrderID
+
+	orderType, err := futuresOrderType(req.Type, req.TimeInForce)
+	if err != nil {
+		return nil, err
+	}
+	req.Type = orderType
+
+	reservation, err := f.client.checkRisk(risk)
 	if err != nil {
 		return nil, err
 	}
 
-	var result OrderResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	resp, err := f.client.doFuturesRequest("POST", "/fapi/v1/trade/order", req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		reservation.release(err)
+		return nil, err
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
+	result, err := decodeData[OrderResponse](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
+	if result.ClientOrderID == "" {
+		result.ClientOrderID = req.ClientOrderID
+	}
+
 	return &result, nil
 }
 
@@ -132,6 +149,17 @@ func (f *FuturesAPI) CancelOrder(futuresName, orderID string) error {
 	return err
 }
 
+// CancelOrderByClientOrderID cancels a futures order by its client order ID
+func (f *FuturesAPI) CancelOrderByClientOrderID(futuresName, clientOrderID string) error {
+	req := map[string]string{
+		"futuresName":   futuresName,
+		"clientOrderId": clientOrderID,
+	}
+
+	_, err := f.client.doFuturesRequest("POST", "/fapi/v1/trade/cancel", req)
+	return err
+}
+
 // CancelAllOrders cancels all futures orders for a symbol
 func (f *FuturesAPI) CancelAllOrders(futuresName string) error {
 	req := map[string]string{
@@ -153,13 +181,8 @@ func (f *FuturesAPI) GetCurrentOrders(futuresName string) ([]FuturesOrder, error
 		return nil, err
 	}
 
-	var result []FuturesOrder
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesOrder](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse orders response: %w", err)
 	}
 
@@ -181,13 +204,8 @@ func (f *FuturesAPI) GetOrderHistory(futuresName string, limit int) ([]FuturesOr
 		return nil, err
 	}
 
-	var result []FuturesOrder
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesOrder](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse orders response: %w", err)
 	}
 
@@ -206,13 +224,28 @@ func (f *FuturesAPI) GetOrderInfo(futuresName, orderID string) (*FuturesOrder, e
 		return nil, err
 	}
 
-	var result FuturesOrder
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[FuturesOrder](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetOrderInfoByClientOrderID gets specific futures order information by its client order ID
+func (f *FuturesAPI) GetOrderInfoByClientOrderID(futuresName, clientOrderID string) (*FuturesOrder, error) {
+	params := map[string]string{
+		"futuresName":   futuresName,
+		"clientOrderId": clientOrderID,
+	}
+
+	resp, err := f.client.doFuturesRequest("GET", "/fapi/v1/trade/order", params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		return nil, err
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
+	result, err := decodeData[FuturesOrder](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
@@ -234,19 +267,170 @@ func (f *FuturesAPI) GetTrades(futuresName string, limit int) ([]FuturesTrade, e
 		return nil, err
 	}
 
-	var result []FuturesTrade
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesTrade](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		return nil, fmt.Errorf("failed to parse trades response: %w", err)
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse trades response: %w", err)
+	return result, nil
+}
+
+// Conditional Order APIs
+
+// validateTrigger rejects a trigger price that is not positive and an unknown trigger type.
+// An empty trigger type is sent as FuturesTriggerTypeLast.
+func validateTrigger(price decimal.Decimal, triggerType string) error {
+	if !price.IsPositive() {
+		return fmt.Errorf("%w %s", ErrInvalidTriggerPrice, price)
+	}
+
+	switch strings.ToUpper(triggerType) {
+	case "", FuturesTriggerTypeLast, FuturesTriggerTypeMark, FuturesTriggerTypeIndex:
+		return nil
+	default:
+		return fmt.Errorf("%w %q", ErrInvalidTriggerType, triggerType)
+	}
+}
+
+// CreateConditionOrder creates a futures conditional order that is placed once the trigger price is reached
+func (f *FuturesAPI) CreateConditionOrder(req FuturesConditionOrderRequest) (*OrderResponse, error) {
+	if err := validateOrder(req.Side, req.Type); err != nil {
+		return nil, err
+	}
+	if err := validateTrigger(req.TriggerPrice, req.TriggerType); err != nil {
+		return nil, err
+	}
+	clientOrderID, err := f.client.nextClientOrderID(req.ClientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	req.ClientOrderID = clientOrderID
+
+	// Zero decimals are still encoded by encoding/json, so a market order's price is left out explicitly
+	params := map[string]string{
+		"futuresName":   req.FuturesName,
+		"type":          req.Type.String(),
+		"side":          req.Side.String(),
+		"open":          req.Open,
+		"positionType":  req.PositionType.String(),
+		"volume":        req.Volume.String(),
+		"triggerPrice":  req.TriggerPrice.String(),
+		"triggerType":   req.TriggerType,
+		"clientOrderId": req.ClientOrderID,
+	}
+	if params["triggerType"] == "" {
+		params["triggerType"] = FuturesTriggerTypeLast
+	}
+	if params["clientOrderId"] == "" {
+		delete(params, "clientOrderId")
+	}
+	if !req.Price.IsZero() {
+		params["price"] = req.Price.String()
+	}
+
+	reservation, err := f.client.checkRisk(futuresRiskOrder(req.FuturesName, req.Side, req.Type, req.Open, req.Price, req.Volume))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.doFuturesRequest("POST", "/fapi/v1/trade/conditionOrder", params)
+	if err != nil {
+		reservation.release(err)
+		return nil, err
+	}
+
+	result, err := decodeData[OrderResponse](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse condition order response: %w", err)
+	}
+
+	if result.ClientOrderID == "" {
+		result.ClientOrderID = req.ClientOrderID
+	}
+
+	return &result, nil
+}
+
+// SetPositionTPSL attaches take-profit and/or stop-loss orders to an open position
+func (f *FuturesAPI) SetPositionTPSL(req FuturesPositionTPSLRequest) (*FuturesPositionTPSLResponse, error) {
+	if req.TakeProfitPrice.IsZero() && req.StopLossPrice.IsZero() {
+		return nil, fmt.Errorf("take profit or stop loss price is required")
+	}
+	if err := validateOrder(req.Side, ""); err != nil {
+		return nil, err
+	}
+	for _, price := range []decimal.Decimal{req.TakeProfitPrice, req.StopLossPrice} {
+		if price.IsZero() {
+			continue
+		}
+		if err := validateTrigger(price, req.TriggerType); err != nil {
+			return nil, err
+		}
+	}
+
+	// Zero decimals are still encoded by encoding/json, so unset fields are left out explicitly
+	params := map[string]string{
+		"futuresName":  req.FuturesName,
+		"side":         req.Side.String(),
+		"positionType": req.PositionType.String(),
+		"triggerType":  req.TriggerType,
+	}
+	if params["triggerType"] == "" {
+		params["triggerType"] = FuturesTriggerTypeLast
+	}
+	if !req.Volume.IsZero() {
+		params["volume"] = req.Volume.String()
+	}
+	if !req.TakeProfitPrice.IsZero() {
+		params["takeProfitPrice"] = req.TakeProfitPrice.String()
+	}
+	if !req.StopLossPrice.IsZero() {
+		params["stopLossPrice"] = req.StopLossPrice.String()
+	}
+
+	resp, err := f.client.doFuturesRequest("POST", "/fapi/v1/trade/positionTpSl", params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeData[FuturesPositionTPSLResponse](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse position tp/sl response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetConditionOrders gets the pending conditional orders of a futures contract
+func (f *FuturesAPI) GetConditionOrders(futuresName string) ([]FuturesConditionOrder, error) {
+	params := map[string]string{
+		"futuresName": futuresName,
+	}
+
+	resp, err := f.client.doFuturesRequest("GET", "/fapi/v1/trade/conditionOrders", params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeData[[]FuturesConditionOrder](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse condition orders response: %w", err)
 	}
 
 	return result, nil
 }
 
+// CancelConditionOrder cancels a pending conditional order
+func (f *FuturesAPI) CancelConditionOrder(futuresName, orderID string) error {
+	req := map[string]string{
+		"futuresName": futuresName,
+		"orderId":     orderID,
+	}
+
+	_, err := f.client.doFuturesRequest("POST", "/fapi/v1/trade/cancelConditionOrder", req)
+	return err
+}
+
 // Position and Account APIs
 
 // GetPositions gets futures positions
@@ -261,13 +445,8 @@ func (f *FuturesAPI) GetPositions(futuresName string) ([]FuturesPosition, error)
 		return nil, err
 	}
 
-	var result []FuturesPosition
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesPosition](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse positions response: %w", err)
 	}
 
@@ -281,13 +460,8 @@ func (f *FuturesAPI) GetAccount() (*FuturesAccount, error) {
 		return nil, err
 	}
 
-	var result FuturesAccount
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[FuturesAccount](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse account response: %w", err)
 	}
 
@@ -335,13 +509,8 @@ func (f *FuturesAPI) GetAllTicker() ([]FuturesTicker, error) {
 		return nil, err
 	}
 
-	var result []FuturesTicker
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesTicker](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse all ticker response: %w", err)
 	}
 
@@ -359,13 +528,8 @@ func (f *FuturesAPI) GetIndexPrice(symbol string) (*FuturesIndexPrice, error) {
 		return nil, err
 	}
 
-	var result FuturesIndexPrice
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[FuturesIndexPrice](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse index price response: %w", err)
 	}
 
@@ -379,13 +543,8 @@ func (f *FuturesAPI) GetAllIndexPrice() ([]FuturesIndexPrice, error) {
 		return nil, err
 	}
 
-	var result []FuturesIndexPrice
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesIndexPrice](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse all index prices response: %w", err)
 	}
 
@@ -399,13 +558,8 @@ func (f *FuturesAPI) GetAllTagIndexPrice() ([]FuturesIndexPrice, error) {
 		return nil, err
 	}
 
-	var result []FuturesIndexPrice
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesIndexPrice](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse tag index prices response: %w", err)
 	}
 
@@ -419,13 +573,8 @@ func (f *FuturesAPI) GetAllFuturesDepth() (map[string]ExchangeDepth, error) {
 		return nil, err
 	}
 
-	var result map[string]ExchangeDepth
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[map[string]ExchangeDepth](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse all futures depth response: %w", err)
 	}
 
@@ -434,18 +583,46 @@ func (f *FuturesAPI) GetAllFuturesDepth() (map[string]ExchangeDepth, error) {
 
 // BatchCreateOrders creates multiple futures orders in batch
 func (f *FuturesAPI) BatchCreateOrders(req FuturesBatchOrderRequest) ([]OrderResponse, error) {
-	resp, err := f.client.doFuturesRequest("POST", "/fapi/v1/batchOrders", req)
+	risks := make([]riskOrder, len(req.Orders))
+	for i, order := range req.Orders {
+		if err := validateOrder(order.Side, order.Type); err != nil {
+			return nil, err
+		}
+		name := order.FuturesName
+		if name == "" {
+			name = req.FuturesName
+		}
+		risks[i] = futuresRiskOrder(name, order.Side, order.Type, order.Open, order.Price, order.Volume)
+	}
+	orders := make([]FuturesCreateOrderRequest, len(req.Orders))
+	for i, order := range req.Orders {
+		orderType, err := futuresOrderType(order.Type, order.TimeInForce)
+		if err != nil {
+			return nil, err
+		}
+		order.Type = orderType
+		clientOrderID, err := f.client.nextClientOrderID(order.ClientOrderID)
+		if err != nil {
+			return nil, err
+		}
+		order.ClientOrderID = clientOrderID
+		orders[i] = order
+	}
+	req.Orders = orders
+
+	reservation, err := f.client.checkRisk(risks...)
 	if err != nil {
 		return nil, err
 	}
 
-	var result []OrderResponse
-	dataBytes, err := json.Marshal(resp.Data)
+	resp, err := f.client.doFuturesRequest("POST", "/fapi/v1/batchOrders", req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		reservation.release(err)
+		return nil, err
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
+	result, err := decodeData[[]OrderResponse](resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse batch orders response: %w", err)
 	}
 
@@ -470,13 +647,8 @@ func (f *FuturesAPI) GetCapital() ([]FuturesCapital, error) {
 		return nil, err
 	}
 
-	var result []FuturesCapital
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesCapital](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse capital response: %w", err)
 	}
 
@@ -490,13 +662,8 @@ func (f *FuturesAPI) GetFutureAccounts() ([]FuturesAccount, error) {
 		return nil, err
 	}
 
-	var result []FuturesAccount
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesAccount](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse futures accounts response: %w", err)
 	}
 
@@ -522,13 +689,8 @@ func (f *FuturesAPI) GetAllPositions() ([]FuturesPosition, error) {
 		return nil, err
 	}
 
-	var result []FuturesPosition
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesPosition](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse all positions response: %w", err)
 	}
 
@@ -542,14 +704,24 @@ func (f *FuturesAPI) GetFutures() ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	var result []map[string]interface{}
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]map[string]interface{}](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
+		return nil, fmt.Errorf("failed to parse futures info response: %w", err)
 	}
 
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse futures info response: %w", err)
+	return result, nil
+}
+
+// GetContracts gets the contract metadata of all futures symbols
+func (f *FuturesAPI) GetContracts() ([]FuturesContract, error) {
+	resp, err := f.client.doFuturesRequest("GET", "/fapi/v1/contracts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeData[[]FuturesContract](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contracts response: %w", err)
 	}
 
 	return result, nil
@@ -570,13 +742,8 @@ func (f *FuturesAPI) GetOpeningOrders(futuresName string, limit int) ([]FuturesO
 		return nil, err
 	}
 
-	var result []FuturesOrder
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesOrder](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse opening orders response: %w", err)
 	}
 
@@ -601,13 +768,8 @@ func (f *FuturesAPI) GetMyTrades(futuresName string, fromId string, limit int) (
 		return nil, err
 	}
 
-	var result []FuturesTrade
-	dataBytes, err := json.Marshal(resp.Data)
+	result, err := decodeData[[]FuturesTrade](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response data: %w", err)
-	}
-
-	if err := json.Unmarshal(dataBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse my trades response: %w", err)
 	}
 
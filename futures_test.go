@@ -1,6 +1,10 @@
 package byex
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 	"testing"
 
 	"github.com/shopspring/decimal"
@@ -693,6 +697,60 @@ func TestFuturesAPI_BatchCancelOrders(t *testing.T) {
 	}
 }
 
+func TestFuturesAPI_ConditionOrders_InvalidTrigger(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s", r.URL.Path)
+	})
+	futures := client.Futures()
+
+	order := FuturesConditionOrderRequest{
+		FuturesName: "E-BTC-USDT",
+		Type:        OrderTypeMarket,
+		Side:        OrderSideSell,
+		Open:        FuturesTradeTypeClose,
+		Volume:      decimal.NewFromInt(1),
+	}
+	if _, err := futures.CreateConditionOrder(order); !errors.Is(err, ErrInvalidTriggerPrice) {
+		t.Errorf("Expected ErrInvalidTriggerPrice for a missing trigger price, got %v", err)
+	}
+
+	order.TriggerPrice = decimal.NewFromInt(42000)
+	order.TriggerType = "BID"
+	if _, err := futures.CreateConditionOrder(order); !errors.Is(err, ErrInvalidTriggerType) {
+		t.Errorf("Expected ErrInvalidTriggerType, got %v", err)
+	}
+
+	_, err := futures.SetPositionTPSL(FuturesPositionTPSLRequest{FuturesName: "E-BTC-USDT", Side: OrderSideSell, StopLossPrice: decimal.NewFromInt(-1)})
+	if !errors.Is(err, ErrInvalidTriggerPrice) {
+		t.Errorf("Expected ErrInvalidTriggerPrice for a negative stop loss, got %v", err)
+	}
+}
+
+func TestFuturesAPI_BatchCancelOrders_Request(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody struct {
+		FuturesName string   `json:"futuresName"`
+		OrderIDList []string `json:"orderIdList"`
+	}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath = r.Method, r.URL.Path
+		json.Unmarshal(body, &gotBody)
+		checkFuturesSignature(t, r, body)
+		writeMockResponse(w, `null`)
+	})
+
+	if err := client.Futures().BatchCancelOrders("E-BTC-USDT", []string{"123", "456"}); err != nil {
+		t.Fatalf("BatchCancelOrders returned error: %v", err)
+	}
+	if gotMethod != "DELETE" || gotPath != "/fapi/v1/batchOrders" {
+		t.Errorf("Expected DELETE /fapi/v1/batchOrders, got %s %s", gotMethod, gotPath)
+	}
+	if gotBody.FuturesName != "E-BTC-USDT" || len(gotBody.OrderIDList) != 2 || gotBody.OrderIDList[1] != "456" {
+		t.Errorf("Unexpected body %+v", gotBody)
+	}
+}
+
 func TestFuturesAPI_GetCapital(t *testing.T) {
 	client := NewClient(testApiKey, testSecretKey, ClientOption{Testnet: true})
 	futures := NewFuturesAPI(client)
@@ -1001,3 +1059,94 @@ func TestFuturesRequestTypes(t *testing.T) {
 		t.Error("FuturesTransferRequest should set type correctly")
 	}
 }
+
+func TestFuturesAPI_ConditionOrders(t *testing.T) {
+	bodies := make(map[string]map[string]interface{})
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			bodies[r.URL.Path] = body
+		}
+
+		switch r.URL.Path {
+		case "/fapi/v1/trade/conditionOrder":
+			writeMockResponse(w, `{"orderId":"c1"}`)
+		case "/fapi/v1/trade/positionTpSl":
+			writeMockResponse(w, `{"stopLossOrderId":"sl1"}`)
+		case "/fapi/v1/trade/conditionOrders":
+			if r.URL.Query().Get("futuresName") != "E-BTC-USDT" {
+				t.Errorf("Unexpected query %v", r.URL.Query())
+			}
+			writeMockResponse(w, `[{"orderId":"c1","type":"MARKET","side":"SELL","triggerPrice":"42000","triggerType":"MARK","status":"NEW"}]`)
+		case "/fapi/v1/trade/cancelConditionOrder":
+			writeMockResponse(w, `null`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+	futures := client.Futures()
+
+	resp, err := futures.CreateConditionOrder(FuturesConditionOrderRequest{
+		FuturesName:  "E-BTC-USDT",
+		Type:         OrderTypeMarket,
+		Side:         OrderSideSell,
+		Open:         FuturesTradeTypeClose,
+		PositionType: FuturesPositionTypeCross,
+		Volume:       decimal.NewFromInt(1),
+		TriggerPrice: decimal.NewFromInt(42000),
+		TriggerType:  FuturesTriggerTypeMark,
+	})
+	if err != nil {
+		t.Fatalf("CreateConditionOrder() returned error: %v", err)
+	}
+	if resp.OrderID != "c1" {
+		t.Errorf("Expected order ID c1, got %s", resp.OrderID)
+	}
+	body := bodies["/fapi/v1/trade/conditionOrder"]
+	if body["triggerPrice"] != "42000" || body["triggerType"] != "MARK" {
+		t.Errorf("Unexpected condition order body: %v", body)
+	}
+	if _, ok := body["price"]; ok {
+		t.Errorf("Expected no price for a market condition order, got %v", body["price"])
+	}
+
+	tpsl, err := futures.SetPositionTPSL(FuturesPositionTPSLRequest{
+		FuturesName:   "E-BTC-USDT",
+		Side:          OrderSideSell,
+		PositionType:  FuturesPositionTypeCross,
+		StopLossPrice: decimal.NewFromInt(40000),
+	})
+	if err != nil {
+		t.Fatalf("SetPositionTPSL() returned error: %v", err)
+	}
+	if tpsl.StopLossOrderID != "sl1" {
+		t.Errorf("Expected stop loss order sl1, got %+v", tpsl)
+	}
+	body = bodies["/fapi/v1/trade/positionTpSl"]
+	if _, ok := body["takeProfitPrice"]; ok {
+		t.Errorf("Unset take profit price should be omitted: %v", body)
+	}
+	if body["stopLossPrice"] != "40000" || body["triggerType"] != FuturesTriggerTypeLast {
+		t.Errorf("Unexpected position tp/sl body: %v", body)
+	}
+
+	if _, err := futures.SetPositionTPSL(FuturesPositionTPSLRequest{FuturesName: "E-BTC-USDT"}); err == nil {
+		t.Error("Expected error without take profit or stop loss price")
+	}
+
+	orders, err := futures.GetConditionOrders("E-BTC-USDT")
+	if err != nil {
+		t.Fatalf("GetConditionOrders() returned error: %v", err)
+	}
+	if len(orders) != 1 || !orders[0].TriggerPrice.Equal(decimal.NewFromInt(42000)) || orders[0].TriggerType != FuturesTriggerTypeMark {
+		t.Errorf("Unexpected condition orders: %+v", orders)
+	}
+
+	if err := futures.CancelConditionOrder("E-BTC-USDT", "c1"); err != nil {
+		t.Errorf("CancelConditionOrder() returned error: %v", err)
+	}
+	if bodies["/fapi/v1/trade/cancelConditionOrder"]["orderId"] != "c1" {
+		t.Errorf("Unexpected cancel body: %v", bodies["/fapi/v1/trade/cancelConditionOrder"])
+	}
+}
@@ -0,0 +1,427 @@
+package byex
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHistoryPageSize = 100
+
+	historyTradesFile  = "trades.jsonl"
+	historyOrdersFile  = "orders.jsonl"
+	historyCursorsFile = "cursors.json"
+)
+
+// HistoryStore keeps spot and futures orders and trades in a directory so reports do not
+// need to download the whole history again. Trades and orders are appended to JSONL files
+// and the last seen ids are kept in a cursor file, so a store reopened after a restart
+// continues syncing where it stopped.
+type HistoryStore struct {
+	dir string
+
+	mu      sync.RWMutex
+	trades  map[string]Fill
+	orders  map[string]ManagedOrder
+	cursors map[string]historyCursor
+}
+
+// historyCursor holds the last seen ids of one symbol
+type historyCursor struct {
+	TradeID string `json:"tradeId,omitempty"`
+	OrderID string `json:"orderId,omitempty"`
+}
+
+// HistoryQuery selects stored trades or orders. Zero fields match everything.
+type HistoryQuery struct {
+	Market OrderMarket
+	Symbol string
+	// Start and End limit the result to [Start, End), by trade time or order creation time
+	Start time.Time
+	End   time.Time
+}
+
+// HistorySyncResult counts what a sync added to the store
+type HistorySyncResult struct {
+	Trades int
+	Orders int
+	// OrderGap is set when the futures order history returned a full page without
+	// reaching the last stored order, so older orders may be missing from the store
+	OrderGap bool
+}
+
+// NewHistoryStore opens the store in dir, creating the directory if needed
+func NewHistoryStore(dir string) (*HistoryStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	s := &HistoryStore{
+		dir:     dir,
+		trades:  make(map[string]Fill),
+		orders:  make(map[string]ManagedOrder),
+		cursors: make(map[string]historyCursor),
+	}
+
+	if err := readJSONLFile(s.path(historyTradesFile), func(line []byte) error {
+		var fill Fill
+		if err := json.Unmarshal(line, &fill); err != nil {
+			return err
+		}
+		s.trades[orderKey(fill.Market, fill.ID)] = fill
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to load trade history: %w", err)
+	}
+
+	// Orders are appended on every change, so the last line of an order wins
+	if err := readJSONLFile(s.path(historyOrdersFile), func(line []byte) error {
+		var order ManagedOrder
+		if err := json.Unmarshal(line, &order); err != nil {
+			return err
+		}
+		s.orders[orderKey(order.Market, order.OrderID)] = order
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to load order history: %w", err)
+	}
+
+	if err := readJSONFile(s.path(historyCursorsFile), &s.cursors); err != nil {
+		return nil, fmt.Errorf("failed to load history cursors: %w", err)
+	}
+
+	return s, nil
+}
+
+// SyncSpot downloads the spot trades and orders of symbol that are not stored yet.
+// Trades continue after the last seen trade id. Order history has no id filter, so it is
+// read newest first until the last seen order id, and stored orders that were still open
+// are refreshed with GetOrderInfo.
+func (s *HistoryStore) SyncSpot(exchange *ExchangeAPI, symbol string) (HistorySyncResult, error) {
+	var result HistorySyncResult
+	cursor := s.cursor(OrderMarketSpot, symbol)
+
+	var fills []Fill
+	lastID, _ := strconv.ParseInt(cursor.TradeID, 10, 64)
+	for page := 1; ; page++ {
+		resp, err := exchange.GetAllTradingRecords(symbol, defaultHistoryPageSize, page, lastID, "", "", 0)
+		if err != nil {
+			return result, fmt.Errorf("failed to get trades for %s: %w", symbol, err)
+		}
+
+		added := 0
+		for _, trade := range resp.ResultList {
+			if trade.Symbol == "" {
+				trade.Symbol = symbol
+			}
+			if fill := FillFromExchangeTrade(trade); !s.hasTrade(fill) {
+				fills = append(fills, fill)
+				added++
+			}
+		}
+
+		// Stop once a page brings nothing new, in case the id filter is ignored
+		if len(resp.ResultList) < defaultHistoryPageSize || added == 0 {
+			break
+		}
+	}
+
+	var orders []ManagedOrder
+	for page := 1; ; page++ {
+		resp, err := exchange.GetOrderHistory(symbol, defaultHistoryPageSize, page)
+		if err != nil {
+			return result, fmt.Errorf("failed to get order history for %s: %w", symbol, err)
+		}
+
+		reached := false
+		for _, order := range resp.ResultList {
+			orders = append(orders, managedFromExchangeOrder(symbol, order))
+			if cursor.OrderID != "" && !idAfter(order.ID, cursor.OrderID) {
+				reached = true
+			}
+		}
+
+		if reached || len(resp.ResultList) < defaultHistoryPageSize {
+			break
+		}
+	}
+
+	for _, order := range s.openOrders(OrderMarketSpot, symbol) {
+		info, err := exchange.GetOrderInfo(symbol, order.OrderID)
+		if err != nil {
+			return result, fmt.Errorf("failed to refresh order %s: %w", order.OrderID, err)
+		}
+		orders = append(orders, managedFromExchangeOrder(symbol, *info))
+	}
+
+	return s.store(OrderMarketSpot, symbol, fills, orders)
+}
+
+// SyncFutures downloads the futures trades and orders of futuresName that are not stored yet.
+// Trades continue from the last seen trade id with fromId. Order history cannot be paged, so
+// only the latest page of GetOrderHistory is read: when it does not reach back to the last
+// stored order, the result has OrderGap set. Sync often enough to keep fewer new orders than
+// a page between syncs. Stored orders that were still open are refreshed with GetOrderInfo.
+func (s *HistoryStore) SyncFutures(futures *FuturesAPI, futuresName string) (HistorySyncResult, error) {
+	cursor := s.cursor(OrderMarketFutures, futuresName)
+
+	loaded, err := LoadFuturesFills(futures, futuresName, cursor.TradeID)
+	if err != nil {
+		return HistorySyncResult{}, err
+	}
+	var fills []Fill
+	for _, fill := range loaded {
+		if !s.hasTrade(fill) {
+			fills = append(fills, fill)
+		}
+	}
+
+	history, err := futures.GetOrderHistory(futuresName, defaultHistoryPageSize)
+	if err != nil {
+		return HistorySyncResult{}, fmt.Errorf("failed to get order history for %s: %w", futuresName, err)
+	}
+	var orders []ManagedOrder
+	reached := false
+	for _, order := range history {
+		orders = append(orders, managedFromFuturesOrder(futuresName, order))
+		if cursor.OrderID != "" && !idAfter(order.OrderID, cursor.OrderID) {
+			reached = true
+		}
+	}
+	gap := !reached && len(history) >= defaultHistoryPageSize
+
+	for _, order := range s.openOrders(OrderMarketFutures, futuresName) {
+		info, err := futures.GetOrderInfo(futuresName, order.OrderID)
+		if err != nil {
+			return HistorySyncResult{}, fmt.Errorf("failed to refresh order %s: %w", order.OrderID, err)
+		}
+		orders = append(orders, managedFromFuturesOrder(futuresName, *info))
+	}
+
+	result, err := s.store(OrderMarketFutures, futuresName, fills, orders)
+	result.OrderGap = gap
+	return result, err
+}
+
+// Trades returns the stored trades matching query in time order
+func (s *HistoryStore) Trades(query HistoryQuery) []Fill {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Fill
+	for _, fill := range s.trades {
+		if query.matches(fill.Market, fill.Symbol, fill.Time) {
+			result = append(result, fill)
+		}
+	}
+
+	sortFills(result)
+	return result
+}
+
+// Orders returns the stored orders matching query ordered by creation time
+func (s *HistoryStore) Orders(query HistoryQuery) []ManagedOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []ManagedOrder
+	for _, order := range s.orders {
+		if query.matches(order.Market, order.Symbol, order.CreatedAt) {
+			result = append(result, order)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].OrderID < result[j].OrderID
+	})
+	return result
+}
+
+// store appends new trades and new or changed orders, then advances the cursor of symbol
+func (s *HistoryStore) store(market OrderMarket, symbol string, fills []Fill, orders []ManagedOrder) (HistorySyncResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result HistorySyncResult
+	cursor := s.cursors[historyCursorKey(market, symbol)]
+
+	var newFills []interface{}
+	for _, fill := range fills {
+		key := orderKey(fill.Market, fill.ID)
+		if _, ok := s.trades[key]; ok {
+			continue
+		}
+		s.trades[key] = fill
+		newFills = append(newFills, fill)
+		if idAfter(fill.ID, cursor.TradeID) {
+			cursor.TradeID = fill.ID
+		}
+	}
+
+	var changed []interface{}
+	for _, order := range orders {
+		key := orderKey(order.Market, order.OrderID)
+		if old, ok := s.orders[key]; ok && sameManagedOrder(old, order) {
+			continue
+		}
+		s.orders[key] = order
+		changed = append(changed, order)
+		if idAfter(order.OrderID, cursor.OrderID) {
+			cursor.OrderID = order.OrderID
+		}
+	}
+
+	// Data is written before the cursor, so a crash in between only causes a re-download
+	if err := appendJSONLFile(s.path(historyTradesFile), newFills); err != nil {
+		return result, fmt.Errorf("failed to save trade history: %w", err)
+	}
+	if err := appendJSONLFile(s.path(historyOrdersFile), changed); err != nil {
+		return result, fmt.Errorf("failed to save order history: %w", err)
+	}
+
+	s.cursors[historyCursorKey(market, symbol)] = cursor
+	if err := writeJSONFile(s.path(historyCursorsFile), s.cursors); err != nil {
+		return result, fmt.Errorf("failed to save history cursors: %w", err)
+	}
+
+	result.Trades = len(newFills)
+	result.Orders = len(changed)
+	return result, nil
+}
+
+func (s *HistoryStore) cursor(market OrderMarket, symbol string) historyCursor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.cursors[historyCursorKey(market, symbol)]
+}
+
+func (s *HistoryStore) hasTrade(fill Fill) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.trades[orderKey(fill.Market, fill.ID)]
+	return ok
+}
+
+// openOrders returns the stored orders of symbol that have not reached a final status
+func (s *HistoryStore) openOrders(market OrderMarket, symbol string) []ManagedOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []ManagedOrder
+	for _, order := range s.orders {
+		if order.Market == market && strings.EqualFold(order.Symbol, symbol) && !order.IsFinal() {
+			result = append(result, order)
+		}
+	}
+	return result
+}
+
+func (s *HistoryStore) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+func historyCursorKey(market OrderMarket, symbol string) string {
+	return string(market) + ":" + strings.ToUpper(symbol)
+}
+
+func (q HistoryQuery) matches(market OrderMarket, symbol string, t time.Time) bool {
+	if q.Market != "" && q.Market != market {
+		return false
+	}
+	if q.Symbol != "" && !strings.EqualFold(q.Symbol, symbol) {
+		return false
+	}
+	if !q.Start.IsZero() && t.Before(q.Start) {
+		return false
+	}
+	return q.End.IsZero() || t.Before(q.End)
+}
+
+// sameManagedOrder reports whether two snapshots of an order hold the same state
+func sameManagedOrder(a, b ManagedOrder) bool {
+	return a.Status == b.Status &&
+		a.FilledAmount.Equal(b.FilledAmount) &&
+		a.AvgPrice.Equal(b.AvgPrice) &&
+		a.UpdatedAt.Equal(b.UpdatedAt)
+}
+
+// idAfter reports whether the numeric id a is greater than b. An empty b is before every id.
+func idAfter(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
+
+// readJSONLFile calls fn with every line of the JSONL file at path. A missing file has no lines.
+// A last line without a newline is the remains of an interrupted append; it is dropped from
+// the file so the next append starts on a new line.
+func readJSONLFile(path string, fn func(line []byte) error) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if end := bytes.LastIndexByte(data, '\n') + 1; end < len(data) {
+		data = data[:end]
+		if err := os.Truncate(path, int64(end)); err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// appendJSONLFile appends values to the JSONL file at path, one per line
+func appendJSONLFile(path string, values []interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, v := range values {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
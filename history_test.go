@@ -0,0 +1,172 @@
+package byex
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryStore_Sync(t *testing.T) {
+	dir := t.TempDir()
+	synced := false
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/open/api/all_trade":
+			if !synced {
+				writeMockResponse(w, `{"count":2,"resultList":[
+					{"id":"11","order_id":"2","symbol":"btcusdt","side":"SELL","amount":"1","price":"200","created_at":1767225600000},
+					{"id":"10","order_id":"1","symbol":"btcusdt","side":"BUY","amount":"1","price":"100","created_at":1767222000000}
+				]}`)
+				return
+			}
+			if query.Get("id") != "11" {
+				t.Errorf("Expected the spot sync to continue after id 11, got %v", query)
+			}
+			writeMockResponse(w, `{"resultList":[
+				{"id":"11","order_id":"2","symbol":"btcusdt","side":"SELL","amount":"1","price":"200","created_at":1767225600000},
+				{"id":"12","order_id":"2","symbol":"btcusdt","side":"SELL","amount":"1","price":"200","created_at":1767229200000}
+			]}`)
+		case "/open/api/v2/all_order":
+			writeMockResponse(w, `{"count":2,"resultList":[
+				{"id":"2","symbol":"btcusdt","side":"SELL","status":"1","amount":"2","created_at":1767225600000},
+				{"id":"1","symbol":"btcusdt","side":"BUY","status":"2","amount":"1","filled_amount":"1","created_at":1767222000000}
+			]}`)
+		case "/open/api/order_info":
+			if query.Get("order_id") != "2" {
+				t.Errorf("Expected only the open order to be refreshed, got %v", query)
+			}
+			writeMockResponse(w, `{"id":"2","symbol":"btcusdt","side":"SELL","status":"2","amount":"2","filled_amount":"2","created_at":1767225600000,"updated_at":1767229200000}`)
+		case "/fapi/v1/userTrades":
+			if synced && query.Get("fromId") != "7" {
+				t.Errorf("Expected the futures sync to continue from id 7, got %v", query)
+			}
+			writeMockResponse(w, `[{"id":"7","order_id":"f1","side":"BUY","volume":"3","price":"100","timestamp":1767222000000}]`)
+		case "/fapi/v1/trade/allOrders":
+			writeMockResponse(w, `[{"orderId":"f1","side":"BUY","status":"FILLED","volume":"3","executedQty":"3","created_at":1767222000000}]`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	store, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("NewHistoryStore() returned error: %v", err)
+	}
+	result, err := store.SyncSpot(client.Exchange(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("SyncSpot() returned error: %v", err)
+	}
+	if result.Trades != 2 || result.Orders != 2 {
+		t.Errorf("Expected 2 trades and 2 orders, got %+v", result)
+	}
+	if _, err := store.SyncFutures(client.Futures(), "E-BTC-USDT"); err != nil {
+		t.Fatalf("SyncFutures() returned error: %v", err)
+	}
+
+	// A reopened store continues from the saved cursors
+	synced = true
+	store, err = NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("NewHistoryStore() returned error: %v", err)
+	}
+	result, err = store.SyncSpot(client.Exchange(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("SyncSpot() returned error: %v", err)
+	}
+	if result.Trades != 1 || result.Orders != 1 {
+		t.Errorf("Expected 1 new trade and 1 updated order, got %+v", result)
+	}
+	result, err = store.SyncFutures(client.Futures(), "E-BTC-USDT")
+	if err != nil {
+		t.Fatalf("SyncFutures() returned error: %v", err)
+	}
+	if result.Trades != 0 || result.Orders != 0 {
+		t.Errorf("Expected nothing new on futures, got %+v", result)
+	}
+
+	spot := store.Trades(HistoryQuery{Market: OrderMarketSpot, Symbol: "btcusdt", Start: time.UnixMilli(1767225600000)})
+	if len(spot) != 2 || spot[0].ID != "11" || spot[1].ID != "12" {
+		t.Errorf("Expected trades 11 and 12, got %+v", spot)
+	}
+	if all := store.Trades(HistoryQuery{}); len(all) != 4 {
+		t.Errorf("Expected 4 trades, got %+v", all)
+	}
+
+	orders := store.Orders(HistoryQuery{Market: OrderMarketSpot})
+	if len(orders) != 2 || orders[1].OrderID != "2" || orders[1].Status != OrderStatusFilled {
+		t.Errorf("Expected order 2 to be filled, got %+v", orders)
+	}
+}
+
+func TestHistoryStore_InterruptedAppend(t *testing.T) {
+	dir := t.TempDir()
+	line := `{"market":"SPOT","id":"1","orderId":"1","symbol":"BTCUSDT","side":"BUY","amount":"1","price":"100","fee":"0","time":"2026-01-01T00:00:00Z"}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, historyTradesFile), []byte(line+`{"market":"SP`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("NewHistoryStore() returned error: %v", err)
+	}
+	if trades := store.Trades(HistoryQuery{}); len(trades) != 1 {
+		t.Errorf("Expected the complete trade to be kept, got %+v", trades)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, historyTradesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != line {
+		t.Errorf("Expected the partial line to be dropped, got %q", data)
+	}
+}
+
+func TestHistoryStore_FuturesOrderGap(t *testing.T) {
+	firstID := 1
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/userTrades":
+			writeMockResponse(w, `[]`)
+		case "/fapi/v1/trade/allOrders":
+			// A full page, newest first
+			var orders []string
+			for id := firstID + defaultHistoryPageSize - 1; id >= firstID; id-- {
+				orders = append(orders, fmt.Sprintf(`{"orderId":"%d","status":"FILLED"}`, id))
+			}
+			writeMockResponse(w, "["+strings.Join(orders, ",")+"]")
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	store, err := NewHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewHistoryStore() returned error: %v", err)
+	}
+	result, err := store.SyncFutures(client.Futures(), "E-BTC-USDT")
+	if err != nil {
+		t.Fatalf("SyncFutures() returned error: %v", err)
+	}
+	if !result.OrderGap {
+		t.Errorf("Expected a gap for a full first page, got %+v", result)
+	}
+
+	// The next page overlaps the stored orders
+	firstID = 50
+	if result, _ = store.SyncFutures(client.Futures(), "E-BTC-USDT"); result.OrderGap || result.Orders != 49 {
+		t.Errorf("Expected 49 new orders without a gap, got %+v", result)
+	}
+
+	// The next page starts after the last stored order
+	firstID = 500
+	if result, _ = store.SyncFutures(client.Futures(), "E-BTC-USDT"); !result.OrderGap {
+		t.Errorf("Expected a gap when the page does not reach the stored orders, got %+v", result)
+	}
+}
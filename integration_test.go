@@ -389,8 +389,11 @@ func TestSignatureGeneration(t *testing.T) {
 		"price":  "45000",
 	}
 
-	signature1 := client.generateExchangeSignature(params)
-	signature2 := client.generateExchangeSignature(params)
+	signature1, err := client.generateExchangeSignature(params)
+	if err != nil {
+		t.Fatalf("Exchange signature generation failed: %v", err)
+	}
+	signature2, _ := client.generateExchangeSignature(params)
 
 	if signature1 == "" {
 		t.Error("Exchange signature should not be empty")
@@ -403,8 +406,11 @@ func TestSignatureGeneration(t *testing.T) {
 
 	// Test futures signature generation
 	timestamp := int64(1640995200000)
-	futuresSignature1 := client.generateFuturesSignature("POST", "/fapi/v1/trade/order", "", timestamp)
-	futuresSignature2 := client.generateFuturesSignature("POST", "/fapi/v1/trade/order", "", timestamp)
+	futuresSignature1, err := client.generateFuturesSignature("POST", "/fapi/v1/trade/order", "", timestamp)
+	if err != nil {
+		t.Fatalf("Futures signature generation failed: %v", err)
+	}
+	futuresSignature2, _ := client.generateFuturesSignature("POST", "/fapi/v1/trade/order", "", timestamp)
 
 	if futuresSignature1 == "" {
 		t.Error("Futures signature should not be empty")
@@ -416,7 +422,7 @@ func TestSignatureGeneration(t *testing.T) {
 	}
 
 	// Different methods should produce different signatures
-	getSignature := client.generateFuturesSignature("GET", "/fapi/v1/trade/order", "", timestamp)
+	getSignature, _ := client.generateFuturesSignature("GET", "/fapi/v1/trade/order", "", timestamp)
 	if futuresSignature1 == getSignature {
 		t.Error("Different HTTP methods should produce different signatures")
 	}
@@ -494,7 +500,7 @@ func BenchmarkSignatureGeneration(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = client.generateExchangeSignature(params)
+		_, _ = client.generateExchangeSignature(params)
 	}
 }
 
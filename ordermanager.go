@@ -0,0 +1,442 @@
+package byex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrOrderNotTracked is returned when an update refers to an order the manager does not know
+	ErrOrderNotTracked = errors.New("order not tracked")
+	// ErrInvalidOrderTransition is returned when an update would move an order backwards in its lifecycle
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+	// ErrUnknownOrderStatus is returned when an update carries a status the manager cannot map
+	ErrUnknownOrderStatus = errors.New("unknown order status")
+)
+
+// OrderMarket identifies the API an order was placed on
+type OrderMarket string
+
+const (
+	OrderMarketSpot    OrderMarket = "SPOT"
+	OrderMarketFutures OrderMarket = "FUTURES"
+)
+
+// ManagedOrder represents an order tracked by an OrderManager
+type ManagedOrder struct {
+	OrderID       string          `json:"orderId"`
+	ClientOrderID string          `json:"clientOrderId,omitempty"`
+	Market        OrderMarket     `json:"market"`
+	Symbol        string          `json:"symbol"` // symbol for spot orders, futuresName for futures orders
+	Side          OrderSide       `json:"side"`
+	Type          OrderType       `json:"type"`
+	Price         decimal.Decimal `json:"price"`
+	Amount        decimal.Decimal `json:"amount"`
+	FilledAmount  decimal.Decimal `json:"filledAmount"`
+	AvgPrice      decimal.Decimal `json:"avgPrice"`
+	Status        OrderStatus     `json:"status"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
+
+// IsFinal reports whether the order reached a terminal status
+func (o ManagedOrder) IsFinal() bool {
+	return isFinalOrderStatus(string(o.Status))
+}
+
+// OrderUpdate represents a status change of a tracked order, from polling or a stream
+type OrderUpdate struct {
+	Market       OrderMarket
+	OrderID      string
+	Status       OrderStatus
+	FilledAmount decimal.Decimal
+	AvgPrice     decimal.Decimal
+}
+
+// OrderManager records orders placed through ExchangeAPI/FuturesAPI and
+// follows them through their lifecycle:
+//
+//	NEW -> PARTIALLY_FILLED -> FILLED
+//	NEW | PARTIALLY_FILLED  -> CANCELLED
+//	NEW                     -> REJECTED
+type OrderManager struct {
+	exchange *ExchangeAPI
+	futures  *FuturesAPI
+
+	mu         sync.RWMutex
+	orders     map[string]*ManagedOrder
+	onComplete []func(ManagedOrder)
+	onUpdate   []func(ManagedOrder)
+}
+
+// NewOrderManager creates a new order manager.
+// exchange or futures may be nil if orders are only placed on the other API.
+func NewOrderManager(exchange *ExchangeAPI, futures *FuturesAPI) *OrderManager {
+	return &OrderManager{
+		exchange: exchange,
+		futures:  futures,
+		orders:   make(map[string]*ManagedOrder),
+	}
+}
+
+func orderKey(market OrderMarket, orderID string) string {
+	return string(market) + ":" + orderID
+}
+
+// OnComplete registers a callback invoked once an order reaches FILLED, CANCELLED or REJECTED
+func (m *OrderManager) OnComplete(fn func(ManagedOrder)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onComplete = append(m.onComplete, fn)
+}
+
+// OnUpdate registers a callback invoked after an order is tracked, updated or forgotten
+func (m *OrderManager) OnUpdate(fn func(ManagedOrder)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onUpdate = append(m.onUpdate, fn)
+}
+
+// notifyUpdate calls the OnUpdate callbacks with order. The caller must not hold m.mu.
+func (m *OrderManager) notifyUpdate(order ManagedOrder) {
+	m.mu.RLock()
+	callbacks := m.onUpdate
+	m.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn(order)
+	}
+}
+
+// CreateOrder places a spot order and starts tracking it
+func (m *OrderManager) CreateOrder(req CreateOrderRequest) (*ManagedOrder, error) {
+	if m.exchange == nil {
+		return nil, errors.New("order manager has no exchange API")
+	}
+
+	resp, err := m.exchange.CreateOrder(req)
+	if resp == nil {
+		return nil, err
+	}
+
+	order := m.Track(ManagedOrder{
+		OrderID:       resp.OrderID,
+		ClientOrderID: resp.ClientOrderID,
+		Market:        OrderMarketSpot,
+		Symbol:        req.Symbol,
+		Side:          OrderSide(req.Side.String()),
+		Type:          OrderType(req.Type.String()),
+		Price:         req.Price,
+		Amount:        req.Amount,
+	})
+
+	// An order can be placed while a follow-up step fails (e.g. the IOC residual
+	// cancel), so it is tracked and returned together with the error
+	return &order, err
+}
+
+// CreateFuturesOrder places a futures order and starts tracking it
+func (m *OrderManager) CreateFuturesOrder(req FuturesCreateOrderRequest) (*ManagedOrder, error) {
+	if m.futures == nil {
+		return nil, errors.New("order manager has no futures API")
+	}
+
+	resp, err := m.futures.CreateOrder(req)
+	if resp == nil {
+		return nil, err
+	}
+
+	order := m.Track(ManagedOrder{
+		OrderID:       resp.OrderID,
+		ClientOrderID: resp.ClientOrderID,
+		Market:        OrderMarketFutures,
+		Symbol:        req.FuturesName,
+		Side:          OrderSide(req.Side.String()),
+		Type:          OrderType(req.Type.String()),
+		Price:         req.Price,
+		Amount:        req.Volume,
+	})
+
+	return &order, err
+}
+
+// Track starts tracking an order placed outside the manager.
+// An empty status is treated as NEW. The tracked copy is returned.
+func (m *OrderManager) Track(order ManagedOrder) ManagedOrder {
+	now := time.Now()
+	order.Status = OrderStatus(normalizeOrderStatus(string(order.Status)))
+	if order.Status == "" {
+		order.Status = OrderStatusNew
+	}
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = now
+	}
+	order.UpdatedAt = now
+
+	m.mu.Lock()
+	m.orders[orderKey(order.Market, order.OrderID)] = &order
+	m.mu.Unlock()
+
+	m.notifyUpdate(order)
+
+	return order
+}
+
+// Restore tracks orders loaded from a journal, keeping their status and timestamps
+func (m *OrderManager) Restore(orders []ManagedOrder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range orders {
+		order := orders[i]
+		m.orders[orderKey(order.Market, order.OrderID)] = &order
+	}
+}
+
+// Forget stops tracking an order
+func (m *OrderManager) Forget(market OrderMarket, orderID string) {
+	key := orderKey(market, orderID)
+
+	m.mu.Lock()
+	order, ok := m.orders[key]
+	delete(m.orders, key)
+	m.mu.Unlock()
+
+	if ok {
+		m.notifyUpdate(*order)
+	}
+}
+
+// Order returns a tracked order
+func (m *OrderManager) Order(market OrderMarket, orderID string) (ManagedOrder, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	order, ok := m.orders[orderKey(market, orderID)]
+	if !ok {
+		return ManagedOrder{}, false
+	}
+
+	return *order, true
+}
+
+// Orders returns all tracked orders sorted by creation time
+func (m *OrderManager) Orders() []ManagedOrder {
+	return m.filter(func(ManagedOrder) bool { return true })
+}
+
+// OpenOrders returns tracked orders that are not final, sorted by creation time
+func (m *OrderManager) OpenOrders() []ManagedOrder {
+	return m.filter(func(o ManagedOrder) bool { return !o.IsFinal() })
+}
+
+// OpenOrdersBySymbol returns open orders for a symbol or futuresName
+func (m *OrderManager) OpenOrdersBySymbol(market OrderMarket, symbol string) []ManagedOrder {
+	return m.filter(func(o ManagedOrder) bool {
+		return !o.IsFinal() && o.Market == market && o.Symbol == symbol
+	})
+}
+
+func (m *OrderManager) filter(keep func(ManagedOrder) bool) []ManagedOrder {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]ManagedOrder, 0, len(m.orders))
+	for _, order := range m.orders {
+		if keep(*order) {
+			result = append(result, *order)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result
+}
+
+// Update merges a status update into a tracked order.
+// Stale updates that would move an order backwards return ErrInvalidOrderTransition,
+// and unrecognised statuses return ErrUnknownOrderStatus; both leave the order unchanged.
+func (m *OrderManager) Update(update OrderUpdate) error {
+	status := normalizeOrderStatus(string(update.Status))
+	if status != "" && !isKnownOrderStatus(status) {
+		return fmt.Errorf("%w: %q for order %s", ErrUnknownOrderStatus, update.Status, update.OrderID)
+	}
+
+	m.mu.Lock()
+	order, ok := m.orders[orderKey(update.Market, update.OrderID)]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s %s", ErrOrderNotTracked, update.Market, update.OrderID)
+	}
+
+	if status != "" && !canTransitionOrder(string(order.Status), status) {
+		from := order.Status
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidOrderTransition, from, status)
+	}
+
+	wasFinal := order.IsFinal()
+	if status != "" {
+		order.Status = OrderStatus(status)
+	}
+	if update.FilledAmount.GreaterThan(order.FilledAmount) {
+		order.FilledAmount = update.FilledAmount
+	}
+	if !update.AvgPrice.IsZero() {
+		order.AvgPrice = update.AvgPrice
+	}
+	order.UpdatedAt = time.Now()
+
+	snapshot := *order
+	callbacks := append([]func(ManagedOrder){}, m.onUpdate...)
+	if !wasFinal && order.IsFinal() {
+		callbacks = append(callbacks, m.onComplete...)
+	}
+	m.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(snapshot)
+	}
+
+	return nil
+}
+
+// ApplyExchangeOrder merges a spot order snapshot into the tracked order
+func (m *OrderManager) ApplyExchangeOrder(order ExchangeOrder) error {
+	return m.Update(OrderUpdate{
+		Market:       OrderMarketSpot,
+		OrderID:      order.ID,
+		Status:       order.Status,
+		FilledAmount: order.FilledAmount,
+		AvgPrice:     order.AvgPrice,
+	})
+}
+
+// ApplyFuturesOrder merges a futures order snapshot into the tracked order
+func (m *OrderManager) ApplyFuturesOrder(order FuturesOrder) error {
+	return m.Update(OrderUpdate{
+		Market:       OrderMarketFutures,
+		OrderID:      order.OrderID,
+		Status:       order.Status,
+		FilledAmount: order.ExecutedQty,
+		AvgPrice:     order.AvgPrice,
+	})
+}
+
+// Poll queries every open order once and merges the results.
+// Stale updates are ignored; request errors and unknown statuses are joined and returned.
+func (m *OrderManager) Poll() error {
+	var errs []error
+	for _, order := range m.OpenOrders() {
+		var err error
+		switch order.Market {
+		case OrderMarketSpot:
+			if m.exchange == nil {
+				continue
+			}
+			var info *ExchangeOrder
+			if info, err = m.exchange.GetOrderInfo(order.Symbol, order.OrderID); err == nil {
+				info.ID = order.OrderID
+				err = m.ApplyExchangeOrder(*info)
+			}
+		case OrderMarketFutures:
+			if m.futures == nil {
+				continue
+			}
+			var info *FuturesOrder
+			if info, err = m.futures.GetOrderInfo(order.Symbol, order.OrderID); err == nil {
+				info.OrderID = order.OrderID
+				err = m.ApplyFuturesOrder(*info)
+			}
+		}
+
+		if err != nil && !errors.Is(err, ErrInvalidOrderTransition) {
+			errs = append(errs, fmt.Errorf("failed to poll order %s: %w", order.OrderID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run polls open orders every interval until ctx is done.
+// Poll errors are passed to onError if it is not nil.
+func (m *OrderManager) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := m.Poll(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// normalizeOrderStatus maps the status spellings used by spot and futures APIs
+// onto the OrderStatus constants. Pending states that do not change the
+// lifecycle (e.g. PENDING_CANCEL) map to an empty string, and unknown
+// statuses are returned upper-cased so callers can report them.
+func normalizeOrderStatus(status string) string {
+	s := strings.ToUpper(strings.TrimSpace(status))
+	s = strings.ReplaceAll(s, " ", "_")
+
+	switch s {
+	case "INIT", "NEW", "0", "1":
+		return OrderStatusNew
+	case "PARTIALLY_FILLED", "PART_FILLED", "PARTIAL_FILLED", "3":
+		return OrderStatusPartiallyFilled
+	case "FILLED", "2":
+		return OrderStatusFilled
+	case "CANCELLED", "CANCELED", "PARTIALLY_CANCELLED", "PARTIALLY_CANCELED", "EXPIRED", "4", "6":
+		return OrderStatusCancelled
+	case "REJECTED", "7":
+		return OrderStatusRejected
+	case "PENDING_CANCEL", "5":
+		return ""
+	default:
+		return s
+	}
+}
+
+func isKnownOrderStatus(status string) bool {
+	return status == OrderStatusNew || status == OrderStatusPartiallyFilled || isFinalOrderStatus(status)
+}
+
+func isFinalOrderStatus(status string) bool {
+	switch status {
+	case OrderStatusFilled, OrderStatusCancelled, OrderStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// canTransitionOrder reports whether an order may move from one status to another
+func canTransitionOrder(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	switch from {
+	case OrderStatusNew:
+		return to == OrderStatusPartiallyFilled || isFinalOrderStatus(to)
+	case OrderStatusPartiallyFilled:
+		return to == OrderStatusFilled || to == OrderStatusCancelled
+	default:
+		return false
+	}
+}
@@ -0,0 +1,236 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNormalizeOrderStatus(t *testing.T) {
+	tests := map[string]string{
+		"new":              OrderStatusNew,
+		"INIT":             OrderStatusNew,
+		"PART_FILLED":      OrderStatusPartiallyFilled,
+		"partially_filled": OrderStatusPartiallyFilled,
+		"Filled":           OrderStatusFilled,
+		"CANCELED":         OrderStatusCancelled,
+		"EXPIRED":          OrderStatusCancelled,
+		"rejected":         OrderStatusRejected,
+		"PENDING_CANCEL":   "",
+		"2":                OrderStatusFilled,
+		"4":                OrderStatusCancelled,
+	}
+
+	for input, expected := range tests {
+		if got := normalizeOrderStatus(input); got != expected {
+			t.Errorf("normalizeOrderStatus(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestCanTransitionOrder(t *testing.T) {
+	tests := []struct {
+		from, to string
+		expected bool
+	}{
+		{OrderStatusNew, OrderStatusPartiallyFilled, true},
+		{OrderStatusNew, OrderStatusFilled, true},
+		{OrderStatusNew, OrderStatusRejected, true},
+		{OrderStatusPartiallyFilled, OrderStatusPartiallyFilled, true},
+		{OrderStatusPartiallyFilled, OrderStatusCancelled, true},
+		{OrderStatusPartiallyFilled, OrderStatusNew, false},
+		{OrderStatusPartiallyFilled, OrderStatusRejected, false},
+		{OrderStatusFilled, OrderStatusCancelled, false},
+		{OrderStatusCancelled, OrderStatusNew, false},
+	}
+
+	for _, tt := range tests {
+		if got := canTransitionOrder(tt.from, tt.to); got != tt.expected {
+			t.Errorf("canTransitionOrder(%s, %s) = %v, expected %v", tt.from, tt.to, got, tt.expected)
+		}
+	}
+}
+
+func TestOrderManager_Update(t *testing.T) {
+	m := NewOrderManager(nil, nil)
+
+	var completed []ManagedOrder
+	m.OnComplete(func(o ManagedOrder) {
+		completed = append(completed, o)
+	})
+
+	m.Track(ManagedOrder{OrderID: "1", Market: OrderMarketSpot, Symbol: "BTCUSDT", Amount: decimal.NewFromInt(2)})
+	m.Track(ManagedOrder{OrderID: "2", Market: OrderMarketFutures, Symbol: "E-BTC-USDT", Amount: decimal.NewFromInt(5)})
+
+	if len(m.OpenOrders()) != 2 {
+		t.Fatalf("Expected 2 open orders, got %d", len(m.OpenOrders()))
+	}
+
+	err := m.ApplyExchangeOrder(ExchangeOrder{ID: "1", Status: "PART_FILLED", FilledAmount: decimal.NewFromInt(1), AvgPrice: decimal.NewFromInt(100)})
+	if err != nil {
+		t.Fatalf("ApplyExchangeOrder() returned error: %v", err)
+	}
+
+	order, _ := m.Order(OrderMarketSpot, "1")
+	if order.Status != OrderStatusPartiallyFilled || !order.FilledAmount.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Unexpected order after partial fill: %+v", order)
+	}
+
+	// A stale NEW snapshot must not move the order backwards
+	err = m.ApplyExchangeOrder(ExchangeOrder{ID: "1", Status: "NEW"})
+	if !errors.Is(err, ErrInvalidOrderTransition) {
+		t.Errorf("Expected ErrInvalidOrderTransition, got %v", err)
+	}
+
+	if err := m.ApplyExchangeOrder(ExchangeOrder{ID: "1", Status: "FILLED", FilledAmount: decimal.NewFromInt(2)}); err != nil {
+		t.Fatalf("ApplyExchangeOrder() returned error: %v", err)
+	}
+	if err := m.ApplyExchangeOrder(ExchangeOrder{ID: "1", Status: "FILLED", FilledAmount: decimal.NewFromInt(2)}); err != nil {
+		t.Fatalf("Repeated final update returned error: %v", err)
+	}
+
+	if len(completed) != 1 || completed[0].OrderID != "1" || completed[0].Status != OrderStatusFilled {
+		t.Errorf("Expected one completion callback for order 1, got %+v", completed)
+	}
+
+	open := m.OpenOrdersBySymbol(OrderMarketFutures, "E-BTC-USDT")
+	if len(open) != 1 || open[0].OrderID != "2" {
+		t.Errorf("Unexpected open futures orders: %+v", open)
+	}
+
+	if err := m.ApplyFuturesOrder(FuturesOrder{OrderID: "missing", Status: "FILLED"}); !errors.Is(err, ErrOrderNotTracked) {
+		t.Errorf("Expected ErrOrderNotTracked, got %v", err)
+	}
+
+	if err := m.ApplyFuturesOrder(FuturesOrder{OrderID: "2", Status: "HALTED"}); !errors.Is(err, ErrUnknownOrderStatus) {
+		t.Errorf("Expected ErrUnknownOrderStatus, got %v", err)
+	}
+}
+
+func TestOrderManager_PollUnknownStatus(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeMockResponse(w, `{"id":"1","status":"HALTED"}`)
+	})
+
+	m := NewOrderManager(client.Exchange(), nil)
+	m.Track(ManagedOrder{OrderID: "1", Market: OrderMarketSpot, Symbol: "BTCUSDT", Status: OrderStatusNew})
+
+	if err := m.Poll(); !errors.Is(err, ErrUnknownOrderStatus) {
+		t.Errorf("Expected Poll() to surface ErrUnknownOrderStatus, got %v", err)
+	}
+}
+
+func TestOrderManager_CreateAndPoll(t *testing.T) {
+	var polls int32
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/create_order":
+			writeMockResponse(w, `{"orderId":"100"}`)
+		case "/open/api/order_info":
+			if atomic.AddInt32(&polls, 1) == 1 {
+				writeMockResponse(w, `{"id":"100","status":"PARTIALLY_FILLED","filled_amount":"0.5"}`)
+			} else {
+				writeMockResponse(w, `{"id":"100","status":"FILLED","filled_amount":"1","avg_price":"45000"}`)
+			}
+		case "/fapi/v1/trade/order":
+			if r.Method == "POST" {
+				writeMockResponse(w, `{"orderId":"200"}`)
+			} else {
+				writeMockResponse(w, `{"orderId":"200","status":"CANCELED","executedQty":"0"}`)
+			}
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	m := NewOrderManager(client.Exchange(), client.Futures())
+
+	done := make(chan ManagedOrder, 2)
+	m.OnComplete(func(o ManagedOrder) { done <- o })
+
+	spot, err := m.CreateOrder(CreateOrderRequest{
+		Symbol: "BTCUSDT",
+		Side:   OrderSideBuy,
+		Type:   OrderTypeLimit,
+		Amount: decimal.NewFromInt(1),
+		Price:  decimal.NewFromInt(45000),
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder() returned error: %v", err)
+	}
+	if spot.OrderID != "100" || spot.Status != OrderStatusNew {
+		t.Errorf("Unexpected spot order: %+v", spot)
+	}
+
+	if _, err := m.CreateFuturesOrder(FuturesCreateOrderRequest{
+		FuturesName: "E-BTC-USDT",
+		Side:        OrderSideSell,
+		Type:        OrderTypeLimit,
+		Volume:      decimal.NewFromInt(10),
+		Price:       decimal.NewFromInt(46000),
+	}); err != nil {
+		t.Fatalf("CreateFuturesOrder() returned error: %v", err)
+	}
+
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+	if order, _ := m.Order(OrderMarketSpot, "100"); order.Status != OrderStatusPartiallyFilled {
+		t.Errorf("Expected spot order partially filled, got %s", order.Status)
+	}
+
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+
+	if len(m.OpenOrders()) != 0 {
+		t.Errorf("Expected no open orders, got %+v", m.OpenOrders())
+	}
+	if len(done) != 2 {
+		t.Errorf("Expected 2 completion callbacks, got %d", len(done))
+	}
+
+	order, _ := m.Order(OrderMarketSpot, "100")
+	if !order.AvgPrice.Equal(decimal.NewFromInt(45000)) || !order.FilledAmount.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Unexpected fill information: %+v", order)
+	}
+}
+
+func TestOrderManager_CreateOrderTracksOnResidualCancelError(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/create_order":
+			writeMockResponse(w, `{"orderId":"7"}`)
+		case "/open/api/cancel_order":
+			fmt.Fprint(w, `{"code":"22","msg":"cancel failed"}`)
+		case "/open/api/order_info":
+			writeMockResponse(w, `{"id":"7","status":"PARTIALLY_FILLED","filled_amount":"0.5"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	m := NewOrderManager(client.Exchange(), nil)
+
+	order, err := m.CreateOrder(CreateOrderRequest{
+		Symbol:      "BTCUSDT",
+		Side:        OrderSideBuy,
+		Type:        OrderTypeLimit,
+		Amount:      decimal.NewFromInt(1),
+		Price:       decimal.NewFromInt(45000),
+		TimeInForce: TimeInForceIOC,
+	})
+	if err == nil {
+		t.Fatal("Expected the residual cancel error")
+	}
+	if order == nil || order.OrderID != "7" {
+		t.Fatalf("Expected the placed order to be returned, got %+v", order)
+	}
+	if _, ok := m.Order(OrderMarketSpot, "7"); !ok {
+		t.Error("Expected the placed order to be tracked")
+	}
+}
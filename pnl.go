@@ -0,0 +1,269 @@
+package byex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostMethod selects how closing fills are matched against open lots
+type CostMethod string
+
+const (
+	// CostMethodFIFO matches closing fills against the oldest open lots first
+	CostMethodFIFO CostMethod = "FIFO"
+	// CostMethodAverage matches closing fills against the average cost of the position
+	CostMethodAverage CostMethod = "AVERAGE"
+)
+
+// PnLOptions configures CalculatePnL
+type PnLOptions struct {
+	// Method defaults to CostMethodFIFO
+	Method CostMethod
+	// Period buckets realized PnL and fees, e.g. 24 * time.Hour for daily UTC figures.
+	// Zero leaves PnLReport.Periods empty.
+	Period time.Duration
+	// ContractMultipliers converts futures volumes into base amounts. Missing names use 1.
+	ContractMultipliers map[string]decimal.Decimal
+}
+
+// SymbolPnL is the PnL of one symbol in its quote currency
+type SymbolPnL struct {
+	Market OrderMarket
+	Symbol string
+	Quote  string
+	// Position is the open amount in base units, negative for shorts, and AvgCost its average entry price
+	Position decimal.Decimal
+	AvgCost  decimal.Decimal
+	Realized decimal.Decimal
+	Fees     decimal.Decimal
+	// MarkPrice is zero, and Unrealized is not calculated, when no price was given for the symbol
+	MarkPrice  decimal.Decimal
+	Unrealized decimal.Decimal
+}
+
+// Net returns the realized PnL after fees
+func (p SymbolPnL) Net() decimal.Decimal {
+	return p.Realized.Sub(p.Fees)
+}
+
+// PeriodPnL is the realized PnL and fees of one symbol over one period
+type PeriodPnL struct {
+	Market   OrderMarket
+	Symbol   string
+	Start    time.Time
+	Realized decimal.Decimal
+	Fees     decimal.Decimal
+}
+
+// PnLReport is the result of CalculatePnL
+type PnLReport struct {
+	Symbols []SymbolPnL
+	Periods []PeriodPnL
+	// UnconvertedFees are fees that could not be converted into the quote currency, by currency.
+	// They are left out of SymbolPnL.Fees.
+	UnconvertedFees map[string]decimal.Decimal
+}
+
+// CalculatePnL matches fills into lots and reports realized and unrealized PnL per symbol.
+// prices are market prices keyed by symbol, e.g. from GetMarketPrices (futures names may be
+// added with their mark price); they mark open positions and convert fees charged in a
+// currency other than the base or quote. Fills may be given in any order.
+//
+// Spot sells without an earlier buy in fills are treated as short lots, so load the whole
+// history of a symbol for correct spot figures.
+func CalculatePnL(fills []Fill, prices map[string]decimal.Decimal, opts PnLOptions) (*PnLReport, error) {
+	if opts.Method == "" {
+		opts.Method = CostMethodFIFO
+	}
+	if opts.Method != CostMethodFIFO && opts.Method != CostMethodAverage {
+		return nil, fmt.Errorf("unknown cost method %s", opts.Method)
+	}
+
+	prices = normalizePrices(prices)
+	sorted := append([]Fill(nil), fills...)
+	sortFills(sorted)
+
+	type symbolKey struct {
+		market OrderMarket
+		symbol string
+	}
+	type periodKey struct {
+		symbolKey
+		start time.Time
+	}
+
+	books := make(map[symbolKey]*lotBook)
+	results := make(map[symbolKey]*SymbolPnL)
+	periods := make(map[periodKey]*PeriodPnL)
+	report := &PnLReport{UnconvertedFees: make(map[string]decimal.Decimal)}
+
+	for _, fill := range sorted {
+		buy := strings.EqualFold(string(fill.Side), OrderSideBuy)
+		if !buy && !strings.EqualFold(string(fill.Side), OrderSideSell) {
+			return nil, fmt.Errorf("unknown side %q of trade %s", fill.Side, fill.ID)
+		}
+
+		key := symbolKey{fill.Market, strings.ToUpper(fill.Symbol)}
+		result, ok := results[key]
+		if !ok {
+			_, quote, _ := splitSymbol(fill.Market, key.symbol)
+			result = &SymbolPnL{Market: key.market, Symbol: key.symbol, Quote: quote}
+			results[key] = result
+			books[key] = &lotBook{method: opts.Method}
+		}
+
+		amount := fill.Amount
+		if fill.Market == OrderMarketFutures {
+			if m, ok := opts.ContractMultipliers[key.symbol]; ok && m.IsPositive() {
+				amount = amount.Mul(m)
+			}
+		}
+
+		realized := books[key].fill(buy, amount, fill.Price)
+		fee, converted := feeInQuote(fill, prices)
+		if !converted {
+			report.UnconvertedFees[fill.FeeCurrency] = report.UnconvertedFees[fill.FeeCurrency].Add(fill.Fee)
+		}
+
+		result.Realized = result.Realized.Add(realized)
+		result.Fees = result.Fees.Add(fee)
+
+		if opts.Period > 0 {
+			pk := periodKey{key, fill.Time.UTC().Truncate(opts.Period)}
+			period, ok := periods[pk]
+			if !ok {
+				period = &PeriodPnL{Market: key.market, Symbol: key.symbol, Start: pk.start}
+				periods[pk] = period
+			}
+			period.Realized = period.Realized.Add(realized)
+			period.Fees = period.Fees.Add(fee)
+		}
+	}
+
+	for key, result := range results {
+		result.Position, result.AvgCost = books[key].position()
+		if mark, ok := prices[key.symbol]; ok && mark.IsPositive() && !result.Position.IsZero() {
+			result.MarkPrice = mark
+			result.Unrealized = mark.Sub(result.AvgCost).Mul(result.Position)
+		}
+		report.Symbols = append(report.Symbols, *result)
+	}
+	sort.Slice(report.Symbols, func(i, j int) bool {
+		if report.Symbols[i].Market != report.Symbols[j].Market {
+			return report.Symbols[i].Market > report.Symbols[j].Market
+		}
+		return report.Symbols[i].Symbol < report.Symbols[j].Symbol
+	})
+
+	for _, period := range periods {
+		report.Periods = append(report.Periods, *period)
+	}
+	sort.Slice(report.Periods, func(i, j int) bool {
+		a, b := report.Periods[i], report.Periods[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		if a.Market != b.Market {
+			return a.Market > b.Market
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	return report, nil
+}
+
+// feeInQuote converts the fee of fill into the quote currency of its symbol.
+// It reports false when there is no price to convert it with.
+func feeInQuote(fill Fill, prices map[string]decimal.Decimal) (decimal.Decimal, bool) {
+	if fill.Fee.IsZero() {
+		return decimal.Zero, true
+	}
+
+	base, quote, ok := splitSymbol(fill.Market, fill.Symbol)
+	currency := strings.ToUpper(fill.FeeCurrency)
+	switch {
+	case currency == "" || ok && currency == quote:
+		return fill.Fee, true
+	case ok && currency == base:
+		return fill.Fee.Mul(fill.Price), true
+	case ok:
+		if price, found := convertPrice(prices, currency, quote); found {
+			return fill.Fee.Mul(price), true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// lot is an open amount bought or sold at one price
+type lot struct {
+	amount decimal.Decimal
+	price  decimal.Decimal
+}
+
+// lotBook holds the open lots of one symbol. All lots are on the same side.
+type lotBook struct {
+	method CostMethod
+	long   bool
+	lots   []lot
+}
+
+// fill applies a trade to the book and returns the PnL it realizes
+func (b *lotBook) fill(buy bool, amount, price decimal.Decimal) decimal.Decimal {
+	realized := decimal.Zero
+
+	// A trade against the open side closes lots first
+	for amount.IsPositive() && len(b.lots) > 0 && b.long != buy {
+		l := &b.lots[0]
+		qty := decimal.Min(amount, l.amount)
+
+		diff := price.Sub(l.price)
+		if !b.long {
+			diff = diff.Neg()
+		}
+		realized = realized.Add(diff.Mul(qty))
+
+		l.amount = l.amount.Sub(qty)
+		amount = amount.Sub(qty)
+		if !l.amount.IsPositive() {
+			b.lots = b.lots[1:]
+		}
+	}
+
+	if amount.IsPositive() {
+		if len(b.lots) == 0 {
+			b.long = buy
+		}
+		if b.method == CostMethodAverage && len(b.lots) == 1 {
+			l := &b.lots[0]
+			total := l.amount.Add(amount)
+			l.price = l.amount.Mul(l.price).Add(amount.Mul(price)).Div(total)
+			l.amount = total
+		} else {
+			b.lots = append(b.lots, lot{amount: amount, price: price})
+		}
+	}
+
+	return realized
+}
+
+// position returns the signed open amount and its average entry price
+func (b *lotBook) position() (decimal.Decimal, decimal.Decimal) {
+	amount, cost := decimal.Zero, decimal.Zero
+	for _, l := range b.lots {
+		amount = amount.Add(l.amount)
+		cost = cost.Add(l.amount.Mul(l.price))
+	}
+	if amount.IsZero() {
+		return decimal.Zero, decimal.Zero
+	}
+
+	avg := cost.Div(amount)
+	if !b.long {
+		amount = amount.Neg()
+	}
+	return amount, avg
+}
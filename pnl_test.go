@@ -0,0 +1,107 @@
+package byex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func testPnLFills() []Fill {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := decimal.RequireFromString
+	return []Fill{
+		// Given out of order on purpose
+		{Market: OrderMarketSpot, ID: "3", Symbol: "BTCUSDT", Side: OrderSideSell, Amount: d("1.5"), Price: d("300"), Fee: d("0.1"), FeeCurrency: "ETH", Time: day.Add(33 * time.Hour)},
+		{Market: OrderMarketSpot, ID: "1", Symbol: "BTCUSDT", Side: OrderSideBuy, Amount: d("1"), Price: d("100"), Fee: d("0.001"), FeeCurrency: "BTC", Time: day.Add(10 * time.Hour)},
+		{Market: OrderMarketSpot, ID: "2", Symbol: "btcusdt", Side: "buy", Amount: d("1"), Price: d("200"), Fee: d("0.2"), FeeCurrency: "USDT", Time: day.Add(12 * time.Hour)},
+		{Market: OrderMarketFutures, ID: "f1", Symbol: "E-BTC-USDT", Side: OrderSideSell, Amount: d("2"), Price: d("100"), Fee: d("0.5"), Time: day.Add(11 * time.Hour)},
+		{Market: OrderMarketFutures, ID: "f2", Symbol: "E-BTC-USDT", Side: OrderSideBuy, Amount: d("4"), Price: d("90"), Fee: d("0.5"), Time: day.Add(35 * time.Hour)},
+	}
+}
+
+func TestCalculatePnL(t *testing.T) {
+	prices := map[string]decimal.Decimal{
+		"btcusdt":    decimal.NewFromInt(400),
+		"ethusdt":    decimal.NewFromInt(10),
+		"E-BTC-USDT": decimal.NewFromInt(95),
+	}
+
+	tests := []struct {
+		method                       CostMethod
+		realized, position, avgCost  string
+		unrealized                   string
+		futuresRealized, futuresUPnL string
+	}{
+		{CostMethodFIFO, "250", "0.5", "200", "100", "10", "5"},
+		{CostMethodAverage, "225", "0.5", "150", "125", "10", "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			report, err := CalculatePnL(testPnLFills(), prices, PnLOptions{
+				Method:              tt.method,
+				Period:              24 * time.Hour,
+				ContractMultipliers: map[string]decimal.Decimal{"E-BTC-USDT": decimal.RequireFromString("0.5")},
+			})
+			if err != nil {
+				t.Fatalf("CalculatePnL() returned error: %v", err)
+			}
+			if len(report.Symbols) != 2 {
+				t.Fatalf("Expected 2 symbols, got %+v", report.Symbols)
+			}
+
+			spot := report.Symbols[0]
+			if spot.Symbol != "BTCUSDT" || spot.Quote != "USDT" {
+				t.Fatalf("Expected BTCUSDT first, got %+v", spot)
+			}
+			checks := []struct {
+				name string
+				got  decimal.Decimal
+				want string
+			}{
+				{"realized", spot.Realized, tt.realized},
+				{"position", spot.Position, tt.position},
+				{"average cost", spot.AvgCost, tt.avgCost},
+				{"unrealized", spot.Unrealized, tt.unrealized},
+				// 0.001 BTC at 100, 0.2 USDT and 0.1 ETH at 10
+				{"fees", spot.Fees, "1.3"},
+				{"futures realized", report.Symbols[1].Realized, tt.futuresRealized},
+				{"futures position", report.Symbols[1].Position, "1"},
+				{"futures unrealized", report.Symbols[1].Unrealized, tt.futuresUPnL},
+			}
+			for _, c := range checks {
+				if !c.got.Equal(decimal.RequireFromString(c.want)) {
+					t.Errorf("Expected %s %s, got %s", c.name, c.want, c.got)
+				}
+			}
+
+			if len(report.Periods) != 4 {
+				t.Fatalf("Expected 4 periods, got %+v", report.Periods)
+			}
+			second := report.Periods[2]
+			if second.Symbol != "BTCUSDT" || !second.Start.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) || !second.Realized.Equal(spot.Realized) {
+				t.Errorf("Unexpected period %+v", second)
+			}
+		})
+	}
+}
+
+func TestCalculatePnL_UnconvertedFees(t *testing.T) {
+	fills := []Fill{{Market: OrderMarketSpot, ID: "1", Symbol: "BTCUSDT", Side: OrderSideBuy, Amount: decimal.NewFromInt(1), Price: decimal.NewFromInt(100), Fee: decimal.NewFromInt(3), FeeCurrency: "XYZ"}}
+
+	report, err := CalculatePnL(fills, nil, PnLOptions{})
+	if err != nil {
+		t.Fatalf("CalculatePnL() returned error: %v", err)
+	}
+	if !report.Symbols[0].Fees.IsZero() || !report.UnconvertedFees["XYZ"].Equal(decimal.NewFromInt(3)) {
+		t.Errorf("Expected the XYZ fee to be reported as unconverted, got %+v and %v", report.Symbols[0], report.UnconvertedFees)
+	}
+	if !report.Symbols[0].MarkPrice.IsZero() || !report.Symbols[0].Unrealized.IsZero() {
+		t.Errorf("Expected no unrealized PnL without a price, got %+v", report.Symbols[0])
+	}
+
+	if _, err := CalculatePnL(fills, nil, PnLOptions{Method: "LIFO"}); err == nil {
+		t.Error("Expected an error for an unknown cost method")
+	}
+}
@@ -0,0 +1,227 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// portfolioBridgeAssets are tried in order to price assets without a direct market
+var portfolioBridgeAssets = []string{"USDT", "BTC", "ETH"}
+
+// PortfolioBalance is the balance of one asset in one account
+type PortfolioBalance struct {
+	Account OrderMarket
+	Asset   string
+	// Free is the available amount, Locked is held by open orders or positions
+	Free   decimal.Decimal
+	Locked decimal.Decimal
+	Amount decimal.Decimal
+	// Price and Value are in the snapshot quote currency, zero when the asset has no price
+	Price decimal.Decimal
+	Value decimal.Decimal
+}
+
+// PortfolioPosition is the exposure of an open futures position
+type PortfolioPosition struct {
+	FuturesName   string
+	Long          bool
+	Amount        decimal.Decimal
+	EntryPrice    decimal.Decimal
+	UnrealizedPnl decimal.Decimal
+	// Notional is the size of the position valued in the snapshot quote currency
+	Notional decimal.Decimal
+}
+
+// PortfolioSnapshot is the valuation of spot and futures accounts at one point in time
+type PortfolioSnapshot struct {
+	Quote string
+	Time  time.Time
+	// Total is the value of all balances. Futures balances include unrealized PnL,
+	// so positions are reported as exposure and not added again.
+	Total     decimal.Decimal
+	ByAsset   map[string]decimal.Decimal
+	ByAccount map[OrderMarket]decimal.Decimal
+	Balances  []PortfolioBalance
+	Positions []PortfolioPosition
+	// Unpriced lists assets that could not be valued in Quote
+	Unpriced []string
+}
+
+// Portfolio values spot balances, futures margin balances and open positions in one quote currency
+type Portfolio struct {
+	exchange *ExchangeAPI
+	futures  *FuturesAPI
+	quote    string
+}
+
+// NewPortfolio creates a portfolio valued in quote, e.g. "USDT".
+// exchange is required for market prices; futures may be nil to leave futures accounts out.
+func NewPortfolio(exchange *ExchangeAPI, futures *FuturesAPI, quote string) *Portfolio {
+	return &Portfolio{
+		exchange: exchange,
+		futures:  futures,
+		quote:    strings.ToUpper(quote),
+	}
+}
+
+// Snapshot reads all balances and positions and values them with GetMarketPrices
+func (p *Portfolio) Snapshot() (*PortfolioSnapshot, error) {
+	if p.exchange == nil {
+		return nil, errors.New("portfolio has no exchange API")
+	}
+
+	raw, err := p.exchange.GetMarketPrices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market prices: %w", err)
+	}
+	prices := normalizePrices(raw)
+
+	var balances []PortfolioBalance
+
+	account, err := p.exchange.GetAccount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot account: %w", err)
+	}
+	for _, coin := range account.CoinList {
+		balances = append(balances, PortfolioBalance{
+			Account: OrderMarketSpot,
+			Asset:   strings.ToUpper(coin.Coin),
+			Free:    coin.Normal,
+			Locked:  coin.Locked,
+			Amount:  coin.Normal.Add(coin.Locked),
+		})
+	}
+
+	var positions []FuturesPosition
+	if p.futures != nil {
+		capital, err := p.futures.GetCapital()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get futures balances: %w", err)
+		}
+		for _, c := range capital {
+			balances = append(balances, PortfolioBalance{
+				Account: OrderMarketFutures,
+				Asset:   strings.ToUpper(c.Asset),
+				Free:    c.AvailableBalance,
+				Locked:  c.MarginBalance.Sub(c.AvailableBalance),
+				Amount:  c.MarginBalance,
+			})
+		}
+
+		positions, err = p.futures.GetAllPositions()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get futures positions: %w", err)
+		}
+	}
+
+	snapshot := &PortfolioSnapshot{
+		Quote:     p.quote,
+		Time:      time.Now(),
+		ByAsset:   make(map[string]decimal.Decimal),
+		ByAccount: make(map[OrderMarket]decimal.Decimal),
+	}
+	unpriced := newStringSet()
+
+	for _, b := range balances {
+		if b.Amount.IsZero() {
+			continue
+		}
+		if price, ok := convertPrice(prices, b.Asset, p.quote); ok {
+			b.Price = price
+			b.Value = b.Amount.Mul(price)
+		} else {
+			unpriced.add(b.Asset)
+		}
+
+		snapshot.Balances = append(snapshot.Balances, b)
+		snapshot.Total = snapshot.Total.Add(b.Value)
+		snapshot.ByAsset[b.Asset] = snapshot.ByAsset[b.Asset].Add(b.Value)
+		snapshot.ByAccount[b.Account] = snapshot.ByAccount[b.Account].Add(b.Value)
+	}
+
+	for _, position := range positions {
+		amount := position.PositionAmt.Abs()
+		if amount.IsZero() {
+			continue
+		}
+
+		notional := position.PositionValue.Abs()
+		if notional.IsZero() {
+			notional = amount.Mul(position.AvgPrice)
+		}
+		_, settle, _ := splitSymbol(OrderMarketFutures, position.Symbol)
+		if price, ok := convertPrice(prices, settle, p.quote); ok {
+			notional = notional.Mul(price)
+		} else {
+			unpriced.add(settle)
+			notional = decimal.Zero
+		}
+
+		snapshot.Positions = append(snapshot.Positions, PortfolioPosition{
+			FuturesName:   position.Symbol,
+			Long:          isLongPosition(position),
+			Amount:        amount,
+			EntryPrice:    position.AvgPrice,
+			UnrealizedPnl: position.UnrealizedPnl,
+			Notional:      notional,
+		})
+	}
+
+	sort.SliceStable(snapshot.Balances, func(i, j int) bool {
+		return snapshot.Balances[i].Value.GreaterThan(snapshot.Balances[j].Value)
+	})
+	snapshot.Unpriced = unpriced.sorted()
+
+	return snapshot, nil
+}
+
+// normalizePrices upper-cases the symbols of a GetMarketPrices result
+func normalizePrices(raw map[string]decimal.Decimal) map[string]decimal.Decimal {
+	prices := make(map[string]decimal.Decimal, len(raw))
+	for symbol, price := range raw {
+		prices[strings.ToUpper(symbol)] = price
+	}
+	return prices
+}
+
+// convertPrice prices asset in quote directly, through the inverse market,
+// or through one of the bridge assets
+func convertPrice(prices map[string]decimal.Decimal, asset, quote string) (decimal.Decimal, bool) {
+	if asset == quote {
+		return decimal.NewFromInt(1), true
+	}
+	if price, ok := directPrice(prices, asset, quote); ok {
+		return price, true
+	}
+
+	for _, bridge := range portfolioBridgeAssets {
+		if bridge == asset || bridge == quote {
+			continue
+		}
+		toBridge, ok := directPrice(prices, asset, bridge)
+		if !ok {
+			continue
+		}
+		if toQuote, ok := directPrice(prices, bridge, quote); ok {
+			return toBridge.Mul(toQuote), true
+		}
+	}
+
+	return decimal.Zero, false
+}
+
+// directPrice prices asset in quote from the asset/quote or quote/asset market
+func directPrice(prices map[string]decimal.Decimal, asset, quote string) (decimal.Decimal, bool) {
+	if price, ok := prices[asset+quote]; ok && price.IsPositive() {
+		return price, true
+	}
+	if price, ok := prices[quote+asset]; ok && price.IsPositive() {
+		return decimal.NewFromInt(1).Div(price), true
+	}
+	return decimal.Zero, false
+}
@@ -0,0 +1,97 @@
+package byex
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPortfolio_Snapshot(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/market":
+			writeMockResponse(w, `{"btcusdt":"50000","ethbtc":"0.05"}`)
+		case "/open/api/user/account":
+			writeMockResponse(w, `{"coin_list":[
+				{"coin":"btc","normal":"1","locked":"0.5"},
+				{"coin":"usdt","normal":"100","locked":"0"},
+				{"coin":"eth","normal":"2","locked":"0"},
+				{"coin":"xyz","normal":"10","locked":"0"},
+				{"coin":"doge","normal":"0","locked":"0"}
+			]}`)
+		case "/fapi/v1/balance":
+			writeMockResponse(w, `[{"asset":"USDT","marginBalance":"1000","availableBalance":"800"}]`)
+		case "/fapi/v1/positionRisk":
+			writeMockResponse(w, `[
+				{"symbol":"E-BTC-USDT","positionSide":"SHORT","positionAmt":"-2","avgPrice":"500","positionValue":"1000","unrealizedPnl":"-20"},
+				{"symbol":"E-ETH-USDT","positionSide":"LONG","positionAmt":"0"}
+			]`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	snapshot, err := NewPortfolio(client.Exchange(), client.Futures(), "usdt").Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() returned error: %v", err)
+	}
+
+	if snapshot.Quote != "USDT" || !snapshot.Total.Equal(decimal.NewFromInt(81100)) {
+		t.Errorf("Expected a total of 81100 USDT, got %s %s", snapshot.Total, snapshot.Quote)
+	}
+
+	byAsset := map[string]string{"BTC": "75000", "ETH": "5000", "USDT": "1100", "XYZ": "0"}
+	for asset, want := range byAsset {
+		if !snapshot.ByAsset[asset].Equal(decimal.RequireFromString(want)) {
+			t.Errorf("Expected %s valued at %s, got %s", asset, want, snapshot.ByAsset[asset])
+		}
+	}
+	if _, ok := snapshot.ByAsset["DOGE"]; ok {
+		t.Error("Expected empty balances to be left out")
+	}
+
+	if !snapshot.ByAccount[OrderMarketSpot].Equal(decimal.NewFromInt(80100)) || !snapshot.ByAccount[OrderMarketFutures].Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Unexpected account breakdown: %v", snapshot.ByAccount)
+	}
+	if len(snapshot.Unpriced) != 1 || snapshot.Unpriced[0] != "XYZ" {
+		t.Errorf("Expected XYZ to be unpriced, got %v", snapshot.Unpriced)
+	}
+	if snapshot.Balances[0].Asset != "BTC" {
+		t.Errorf("Expected balances sorted by value, got %s first", snapshot.Balances[0].Asset)
+	}
+
+	if len(snapshot.Positions) != 1 {
+		t.Fatalf("Expected 1 open position, got %d", len(snapshot.Positions))
+	}
+	position := snapshot.Positions[0]
+	if position.Long || !position.Amount.Equal(decimal.NewFromInt(2)) || !position.Notional.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Unexpected position exposure: %+v", position)
+	}
+}
+
+func TestConvertPrice(t *testing.T) {
+	prices := map[string]decimal.Decimal{
+		"BTCUSDT": decimal.NewFromInt(50000),
+		"ETHBTC":  decimal.RequireFromString("0.05"),
+	}
+
+	tests := []struct {
+		asset, quote string
+		want         string
+		ok           bool
+	}{
+		{"BTC", "USDT", "50000", true},
+		{"USDT", "BTC", "0.00002", true},
+		{"ETH", "USDT", "2500", true},
+		{"USDT", "USDT", "1", true},
+		{"XYZ", "USDT", "0", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := convertPrice(prices, tt.asset, tt.quote)
+		if ok != tt.ok || !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("%s/%s: expected %s %v, got %s %v", tt.asset, tt.quote, tt.want, tt.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrUnknownContract is returned by GetContractSpec when the futures name is not listed
+var ErrUnknownContract = errors.New("unknown futures contract")
+
+// ContractSpec describes the contract of a futures position for risk calculations
+type ContractSpec struct {
+	FuturesName string
+	// Multiplier is the base amount of one contract. CalculatePositionRisk reads zero
+	// as 1; GetPositionRisk looks it up with GetContractSpec.
+	Multiplier decimal.Decimal
+	// MaintenanceMarginRate is the maintenance margin as a fraction of the notional.
+	// When zero, it is derived from the position's MaintenanceMargin.
+	MaintenanceMarginRate decimal.Decimal
+}
+
+// PositionRisk describes how close a futures position is to liquidation
+type PositionRisk struct {
+	FuturesName  string
+	PositionType PositionType
+	Long         bool
+	// Quantity is the position size in base units
+	Quantity      decimal.Decimal
+	EntryPrice    decimal.Decimal
+	MarkPrice     decimal.Decimal
+	Notional      decimal.Decimal
+	UnrealizedPnl decimal.Decimal
+	// InitialMargin is the initial margin reported for the position
+	InitialMargin decimal.Decimal
+	// Margin is the isolated margin of the position, or the wallet balance in cross mode
+	Margin decimal.Decimal
+	// MarginBalance is Margin plus the unrealized PnL
+	MarginBalance     decimal.Decimal
+	MaintenanceMargin decimal.Decimal
+	// MarginRatio is MaintenanceMargin / MarginBalance. The position is liquidated at 1.
+	MarginRatio decimal.Decimal
+	// LiquidationPrice is the estimated mark price at which MarginRatio reaches 1.
+	// It is zero when the margin covers the whole long position.
+	LiquidationPrice decimal.Decimal
+	// Leverage is the effective leverage, Notional / MarginBalance
+	Leverage decimal.Decimal
+}
+
+// CalculatePositionRisk estimates the risk of a linear futures position at markPrice.
+// crossBalance is the wallet balance backing cross positions and is ignored in isolated mode.
+// Cross estimates assume the PnL of the account's other positions does not change.
+func CalculatePositionRisk(position FuturesPosition, spec ContractSpec, markPrice, crossBalance decimal.Decimal) (PositionRisk, error) {
+	multiplier := spec.Multiplier
+	if !multiplier.IsPositive() {
+		multiplier = decimal.NewFromInt(1)
+	}
+
+	name := spec.FuturesName
+	if name == "" {
+		name = position.Symbol
+	}
+
+	quantity := position.PositionAmt.Abs().Mul(multiplier)
+	if !quantity.IsPositive() {
+		return PositionRisk{}, fmt.Errorf("%w: %s", ErrNoPosition, name)
+	}
+	if !markPrice.IsPositive() {
+		return PositionRisk{}, fmt.Errorf("invalid mark price %s for %s", markPrice, name)
+	}
+
+	r := PositionRisk{
+		FuturesName:   name,
+		PositionType:  positionTypeOf(position),
+		Long:          isLongPosition(position),
+		Quantity:      quantity,
+		EntryPrice:    position.AvgPrice,
+		MarkPrice:     markPrice,
+		Notional:      quantity.Mul(markPrice),
+		InitialMargin: position.InitialMargin,
+		Margin:        position.InitialMargin,
+	}
+	if r.PositionType == FuturesPositionTypeCross {
+		r.Margin = crossBalance
+	}
+
+	mmr := spec.MaintenanceMarginRate
+	if !mmr.IsPositive() {
+		mmr = derivedMaintenanceMarginRate(position, quantity)
+	}
+
+	r.UnrealizedPnl = markPrice.Sub(position.AvgPrice).Mul(quantity)
+	if !r.Long {
+		r.UnrealizedPnl = r.UnrealizedPnl.Neg()
+	}
+	r.MarginBalance = r.Margin.Add(r.UnrealizedPnl)
+	r.MaintenanceMargin = r.Notional.Mul(mmr)
+
+	if r.MarginBalance.IsPositive() {
+		r.MarginRatio = r.MaintenanceMargin.Div(r.MarginBalance)
+		r.Leverage = r.Notional.Div(r.MarginBalance)
+	} else {
+		// At or past liquidation
+		r.MarginRatio = decimal.NewFromInt(1)
+	}
+
+	// Solve Margin + PnL(P) = Quantity * P * mmr for the liquidation price P
+	one := decimal.NewFromInt(1)
+	perUnit := r.Margin.Div(quantity)
+	if r.Long {
+		r.LiquidationPrice = position.AvgPrice.Sub(perUnit).Div(one.Sub(mmr))
+	} else {
+		r.LiquidationPrice = position.AvgPrice.Add(perUnit).Div(one.Add(mmr))
+	}
+	if r.LiquidationPrice.IsNegative() {
+		r.LiquidationPrice = decimal.Zero
+	}
+
+	return r, nil
+}
+
+// derivedMaintenanceMarginRate derives the maintenance margin rate from the position's own figures
+func derivedMaintenanceMarginRate(position FuturesPosition, quantity decimal.Decimal) decimal.Decimal {
+	notional := position.PositionValue
+	if !notional.IsPositive() {
+		notional = quantity.Mul(position.AvgPrice)
+	}
+	if !notional.IsPositive() {
+		return decimal.Zero
+	}
+	return position.MaintenanceMargin.Div(notional)
+}
+
+// MarginForLeverage returns the margin to add to bring the position to leverage.
+// A negative result is the margin that can be removed. In cross mode leverage is
+// applied to the position's initial margin rather than the shared wallet balance.
+func (r PositionRisk) MarginForLeverage(leverage decimal.Decimal) (decimal.Decimal, error) {
+	if !leverage.IsPositive() {
+		return decimal.Zero, errors.New("target leverage must be positive")
+	}
+
+	required := r.Notional.Div(leverage)
+	if r.PositionType == FuturesPositionTypeCross {
+		return required.Sub(r.InitialMargin), nil
+	}
+	return required.Sub(r.MarginBalance), nil
+}
+
+// GetContractSpec builds the ContractSpec of futuresName from GetContracts.
+// The maintenance margin rate is left zero, to be derived from the position.
+func (f *FuturesAPI) GetContractSpec(futuresName string) (ContractSpec, error) {
+	contracts, err := f.GetContracts()
+	if err != nil {
+		return ContractSpec{}, fmt.Errorf("failed to get contracts: %w", err)
+	}
+
+	for _, c := range contracts {
+		if c.Symbol == futuresName || c.ContractName == futuresName {
+			return ContractSpec{FuturesName: futuresName, Multiplier: c.Multiplier}, nil
+		}
+	}
+
+	return ContractSpec{}, fmt.Errorf("%w: %s", ErrUnknownContract, futuresName)
+}
+
+// GetPositionRisk calculates the risk of position at the current mark price.
+// The mark price falls back to the index price, and cross positions use the
+// account balance from GetAccount. When spec has no Multiplier, it is looked
+// up with GetContractSpec.
+func (f *FuturesAPI) GetPositionRisk(position FuturesPosition, spec ContractSpec) (*PositionRisk, error) {
+	name := spec.FuturesName
+	if name == "" {
+		name = position.Symbol
+	}
+
+	if !spec.Multiplier.IsPositive() {
+		contract, err := f.GetContractSpec(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get contract multiplier: %w", err)
+		}
+		spec.FuturesName = name
+		spec.Multiplier = contract.Multiplier
+	}
+
+	index, err := f.GetIndexPrice(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mark price: %w", err)
+	}
+	markPrice := index.MarkPrice
+	if !markPrice.IsPositive() {
+		markPrice = index.IndexPrice
+	}
+
+	crossBalance := decimal.Zero
+	if positionTypeOf(position) == FuturesPositionTypeCross {
+		account, err := f.GetAccount()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account balance: %w", err)
+		}
+		crossBalance = account.AccountBalance
+	}
+
+	risk, err := CalculatePositionRisk(position, spec, markPrice, crossBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &risk, nil
+}
@@ -0,0 +1,144 @@
+package byex
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculatePositionRisk_Isolated(t *testing.T) {
+	position := FuturesPosition{
+		Symbol:        "E-BTC-USDT",
+		PositionSide:  "LONG",
+		PositionAmt:   decimal.NewFromInt(1),
+		AvgPrice:      decimal.NewFromInt(100),
+		MarginType:    "ISOLATED",
+		InitialMargin: decimal.NewFromInt(10),
+	}
+	spec := ContractSpec{MaintenanceMarginRate: decimal.RequireFromString("0.005")}
+
+	risk, err := CalculatePositionRisk(position, spec, decimal.NewFromInt(95), decimal.NewFromInt(1000))
+	if err != nil {
+		t.Fatalf("CalculatePositionRisk() returned error: %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  decimal.Decimal
+		want string
+	}{
+		{"unrealized pnl", risk.UnrealizedPnl, "-5"},
+		{"margin balance", risk.MarginBalance, "5"},
+		{"maintenance margin", risk.MaintenanceMargin, "0.475"},
+		{"margin ratio", risk.MarginRatio, "0.095"},
+		{"leverage", risk.Leverage, "19"},
+		{"liquidation price", risk.LiquidationPrice.Round(4), "90.4523"},
+	}
+	for _, c := range checks {
+		if !c.got.Equal(decimal.RequireFromString(c.want)) {
+			t.Errorf("Expected %s %s, got %s", c.name, c.want, c.got)
+		}
+	}
+
+	add, err := risk.MarginForLeverage(decimal.NewFromInt(10))
+	if err != nil {
+		t.Fatalf("MarginForLeverage() returned error: %v", err)
+	}
+	if !add.Equal(decimal.RequireFromString("4.5")) {
+		t.Errorf("Expected 4.5 margin to reach 10x, got %s", add)
+	}
+
+	if _, err := CalculatePositionRisk(FuturesPosition{}, spec, decimal.NewFromInt(95), decimal.Zero); !errors.Is(err, ErrNoPosition) {
+		t.Errorf("Expected ErrNoPosition, got %v", err)
+	}
+}
+
+func TestFuturesAPI_GetPositionRisk_Cross(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/premiumIndex":
+			if r.URL.Query().Get("symbol") != "E-BTC-USDT" {
+				t.Errorf("Unexpected query %v", r.URL.Query())
+			}
+			writeMockResponse(w, `{"symbol":"E-BTC-USDT","indexPrice":"104","markPrice":"105"}`)
+		case "/fapi/v1/account/balance":
+			writeMockResponse(w, `{"accountBalance":"50"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	position := FuturesPosition{
+		Symbol:        "E-BTC-USDT",
+		PositionSide:  "SHORT",
+		PositionAmt:   decimal.NewFromInt(-4),
+		AvgPrice:      decimal.NewFromInt(100),
+		MarginType:    "CROSSED",
+		InitialMargin: decimal.NewFromInt(20),
+	}
+	spec := ContractSpec{
+		FuturesName:           "E-BTC-USDT",
+		Multiplier:            decimal.RequireFromString("0.5"),
+		MaintenanceMarginRate: decimal.RequireFromString("0.01"),
+	}
+
+	risk, err := client.Futures().GetPositionRisk(position, spec)
+	if err != nil {
+		t.Fatalf("GetPositionRisk() returned error: %v", err)
+	}
+
+	if risk.Long || risk.PositionType != FuturesPositionTypeCross {
+		t.Errorf("Expected a cross short, got %+v", risk)
+	}
+	if !risk.MarkPrice.Equal(decimal.NewFromInt(105)) || !risk.Margin.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected mark price 105 and margin 50, got %s and %s", risk.MarkPrice, risk.Margin)
+	}
+	if !risk.MarginBalance.Equal(decimal.NewFromInt(40)) || !risk.MarginRatio.Equal(decimal.RequireFromString("0.0525")) {
+		t.Errorf("Expected margin balance 40 and ratio 0.0525, got %s and %s", risk.MarginBalance, risk.MarginRatio)
+	}
+	if !risk.LiquidationPrice.Round(4).Equal(decimal.RequireFromString("123.7624")) {
+		t.Errorf("Expected liquidation price 123.7624, got %s", risk.LiquidationPrice)
+	}
+
+	add, _ := risk.MarginForLeverage(decimal.NewFromInt(5))
+	if !add.Equal(decimal.NewFromInt(22)) {
+		t.Errorf("Expected 22 margin to reach 5x, got %s", add)
+	}
+}
+
+func TestFuturesAPI_GetPositionRisk_ContractLookup(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/contracts":
+			writeMockResponse(w, `[{"symbol":"E-ETH-USDT","multiplier":"0.1"},{"symbol":"E-BTC-USDT","multiplier":"0.001"}]`)
+		case "/fapi/v1/premiumIndex":
+			writeMockResponse(w, `{"symbol":"E-BTC-USDT","markPrice":"50000"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	position := FuturesPosition{
+		Symbol:        "E-BTC-USDT",
+		PositionSide:  "LONG",
+		PositionAmt:   decimal.NewFromInt(2000),
+		AvgPrice:      decimal.NewFromInt(50000),
+		MarginType:    "ISOLATED",
+		InitialMargin: decimal.NewFromInt(10000),
+	}
+
+	risk, err := client.Futures().GetPositionRisk(position, ContractSpec{})
+	if err != nil {
+		t.Fatalf("GetPositionRisk() returned error: %v", err)
+	}
+	if !risk.Quantity.Equal(decimal.NewFromInt(2)) || risk.FuturesName != "E-BTC-USDT" {
+		t.Errorf("Expected quantity 2 of E-BTC-USDT, got %s of %s", risk.Quantity, risk.FuturesName)
+	}
+
+	position.Symbol = "E-XRP-USDT"
+	if _, err := client.Futures().GetPositionRisk(position, ContractSpec{}); !errors.Is(err, ErrUnknownContract) {
+		t.Errorf("Expected ErrUnknownContract, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package byex
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of outgoing requests
+type RateLimiter interface {
+	// Wait blocks until a request may be sent
+	Wait()
+}
+
+// TokenBucketLimiter is a token bucket RateLimiter safe for concurrent use
+type TokenBucketLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerSecond on average with bursts up to burst
+func NewRateLimiter(requestsPerSecond float64, burst int) *TokenBucketLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &TokenBucketLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available and takes it
+func (l *TokenBucketLimiter) Wait() {
+	if l.rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// Reserve the token now so concurrent callers queue up behind each other
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
@@ -0,0 +1,424 @@
+package byex
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultReconcilePageSize   = 100
+	defaultReconcileTradeLimit = 100
+)
+
+// OrderJournal persists tracked orders across restarts
+type OrderJournal interface {
+	Load() ([]ManagedOrder, error)
+	Save(orders []ManagedOrder) error
+}
+
+// FileOrderJournal stores tracked orders as a JSON file
+type FileOrderJournal struct {
+	Path string
+}
+
+// NewFileOrderJournal creates a journal stored at path
+func NewFileOrderJournal(path string) *FileOrderJournal {
+	return &FileOrderJournal{Path: path}
+}
+
+// Load reads the journal. A missing file is an empty journal.
+func (j *FileOrderJournal) Load() ([]ManagedOrder, error) {
+	var orders []ManagedOrder
+	if err := readJSONFile(j.Path, &orders); err != nil {
+		return nil, fmt.Errorf("failed to load order journal: %w", err)
+	}
+
+	return orders, nil
+}
+
+// Save replaces the journal atomically
+func (j *FileOrderJournal) Save(orders []ManagedOrder) error {
+	if err := writeJSONFile(j.Path, orders); err != nil {
+		return fmt.Errorf("failed to save order journal: %w", err)
+	}
+
+	return nil
+}
+
+// AutoSave keeps journal current by saving all tracked orders after every change of m.
+// Save errors are passed to onError if it is not nil.
+func (m *OrderManager) AutoSave(journal OrderJournal, onError func(error)) {
+	var mu sync.Mutex
+	m.OnUpdate(func(ManagedOrder) {
+		// Serialize saves so an older snapshot never overwrites a newer one
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := journal.Save(m.Orders()); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+// ReconcileOptions configures a reconciliation run
+type ReconcileOptions struct {
+	// Symbols and FuturesNames are checked in addition to those of open journal orders
+	Symbols      []string
+	FuturesNames []string
+
+	// PageSize is the page size used to page through spot open orders
+	PageSize int
+	// TradeLimit is the number of recent trades fetched per symbol
+	TradeLimit int
+
+	// CancelOrphans cancels orders open on the exchange but unknown to the journal
+	CancelOrphans bool
+}
+
+// OrderDiscrepancy describes an order whose local and exchange state disagree
+type OrderDiscrepancy struct {
+	Market  OrderMarket
+	Symbol  string
+	OrderID string
+
+	Local  *ManagedOrder // nil for orphaned orders
+	Remote *ManagedOrder // nil for missing orders
+
+	// Fields lists the drifted fields of a drifted order
+	Fields []string
+	// TradedAmount is the amount found in recent trades for the order
+	TradedAmount decimal.Decimal
+
+	Cancelled   bool
+	CancelError error
+}
+
+// ReconcileReport is the result of a reconciliation run
+type ReconcileReport struct {
+	// Orphaned orders are open on the exchange but unknown to the journal
+	Orphaned []OrderDiscrepancy
+	// Missing orders are open in the journal but no longer open on the exchange
+	Missing []OrderDiscrepancy
+	// Drifted orders are open on both sides but disagree on status, price or amounts
+	Drifted []OrderDiscrepancy
+	// Matched is the number of open orders that agree on both sides
+	Matched int
+}
+
+// Reconciler compares a local order journal against the orders open on the exchange
+type Reconciler struct {
+	exchange *ExchangeAPI
+	futures  *FuturesAPI
+	journal  OrderJournal
+}
+
+// NewReconciler creates a new reconciler.
+// exchange or futures may be nil to only reconcile the other API.
+func NewReconciler(exchange *ExchangeAPI, futures *FuturesAPI, journal OrderJournal) *Reconciler {
+	return &Reconciler{
+		exchange: exchange,
+		futures:  futures,
+		journal:  journal,
+	}
+}
+
+// Reconcile loads the journal, fetches open orders and recent trades, and reports differences
+func (r *Reconciler) Reconcile(opts ReconcileOptions) (*ReconcileReport, error) {
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultReconcilePageSize
+	}
+	if opts.TradeLimit <= 0 {
+		opts.TradeLimit = defaultReconcileTradeLimit
+	}
+
+	journal, err := r.journal.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	local := make(map[string]ManagedOrder)
+	symbols := newStringSet(opts.Symbols...)
+	futuresNames := newStringSet(opts.FuturesNames...)
+	for _, order := range journal {
+		if order.IsFinal() {
+			continue
+		}
+		local[orderKey(order.Market, order.OrderID)] = order
+		switch order.Market {
+		case OrderMarketSpot:
+			symbols.add(order.Symbol)
+		case OrderMarketFutures:
+			futuresNames.add(order.Symbol)
+		}
+	}
+
+	remote := make(map[string]ManagedOrder)
+	traded := make(map[string]decimal.Decimal)
+
+	if r.exchange != nil {
+		for _, symbol := range symbols.sorted() {
+			orders, err := spotOpenOrders(r.exchange, symbol, opts.PageSize)
+			if err != nil {
+				return nil, err
+			}
+			for _, order := range orders {
+				remote[orderKey(OrderMarketSpot, order.OrderID)] = order
+			}
+
+			trades, err := r.exchange.GetTrades(symbol, opts.TradeLimit, 1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trades for %s: %w", symbol, err)
+			}
+			for _, trade := range trades.ResultList {
+				key := orderKey(OrderMarketSpot, trade.OrderID)
+				traded[key] = traded[key].Add(trade.Amount)
+			}
+		}
+	}
+
+	if r.futures != nil {
+		for _, name := range futuresNames.sorted() {
+			orders, err := futuresOpenOrders(r.futures, name)
+			if err != nil {
+				return nil, err
+			}
+			for _, order := range orders {
+				remote[orderKey(OrderMarketFutures, order.OrderID)] = order
+			}
+
+			trades, err := r.futures.GetMyTrades(name, "", opts.TradeLimit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trades for %s: %w", name, err)
+			}
+			for _, trade := range trades {
+				key := orderKey(OrderMarketFutures, trade.OrderID)
+				traded[key] = traded[key].Add(trade.Volume)
+			}
+		}
+	}
+
+	report := &ReconcileReport{}
+
+	for key, order := range remote {
+		order := order
+		localOrder, ok := local[key]
+		if !ok {
+			d := OrderDiscrepancy{
+				Market:       order.Market,
+				Symbol:       order.Symbol,
+				OrderID:      order.OrderID,
+				Remote:       &order,
+				TradedAmount: traded[key],
+			}
+			if opts.CancelOrphans {
+				d.CancelError = r.cancel(order)
+				d.Cancelled = d.CancelError == nil
+			}
+			report.Orphaned = append(report.Orphaned, d)
+			continue
+		}
+
+		if fields := driftedFields(localOrder, order); len(fields) > 0 {
+			report.Drifted = append(report.Drifted, OrderDiscrepancy{
+				Market:       order.Market,
+				Symbol:       order.Symbol,
+				OrderID:      order.OrderID,
+				Local:        &localOrder,
+				Remote:       &order,
+				Fields:       fields,
+				TradedAmount: traded[key],
+			})
+			continue
+		}
+
+		report.Matched++
+	}
+
+	for key, order := range local {
+		order := order
+		if _, ok := remote[key]; ok {
+			continue
+		}
+		if order.Market == OrderMarketSpot && r.exchange == nil || order.Market == OrderMarketFutures && r.futures == nil {
+			continue
+		}
+		report.Missing = append(report.Missing, OrderDiscrepancy{
+			Market:       order.Market,
+			Symbol:       order.Symbol,
+			OrderID:      order.OrderID,
+			Local:        &order,
+			TradedAmount: traded[key],
+		})
+	}
+
+	sortDiscrepancies(report.Orphaned)
+	sortDiscrepancies(report.Missing)
+	sortDiscrepancies(report.Drifted)
+
+	return report, nil
+}
+
+// spotOpenOrders pages through the spot open orders of a symbol
+func spotOpenOrders(exchange *ExchangeAPI, symbol string, pageSize int) ([]ManagedOrder, error) {
+	var result []ManagedOrder
+	seen := newStringSet()
+	for page := 1; ; page++ {
+		resp, err := exchange.GetCurrentOrders(symbol, pageSize, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open orders for %s: %w", symbol, err)
+		}
+
+		added := 0
+		for _, order := range resp.ResultList {
+			if _, ok := seen[order.ID]; ok {
+				continue
+			}
+			seen.add(order.ID)
+			result = append(result, managedFromExchangeOrder(symbol, order))
+			added++
+		}
+
+		// count may be missing, so only trust it when it is set. A page without new
+		// orders means the server ignores page and would repeat it forever.
+		if len(resp.ResultList) < pageSize || resp.Count > 0 && len(result) >= resp.Count || added == 0 {
+			return result, nil
+		}
+	}
+}
+
+// futuresOpenOrders merges both futures open order endpoints
+func futuresOpenOrders(futures *FuturesAPI, futuresName string) ([]ManagedOrder, error) {
+	current, err := futures.GetCurrentOrders(futuresName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders for %s: %w", futuresName, err)
+	}
+
+	opening, err := futures.GetOpeningOrders(futuresName, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opening orders for %s: %w", futuresName, err)
+	}
+
+	seen := make(map[string]bool)
+	var result []ManagedOrder
+	for _, order := range append(current, opening...) {
+		if seen[order.OrderID] {
+			continue
+		}
+		seen[order.OrderID] = true
+		result = append(result, managedFromFuturesOrder(futuresName, order))
+	}
+
+	return result, nil
+}
+
+func (r *Reconciler) cancel(order ManagedOrder) error {
+	switch order.Market {
+	case OrderMarketSpot:
+		return r.exchange.CancelOrder(order.Symbol, order.OrderID)
+	case OrderMarketFutures:
+		return r.futures.CancelOrder(order.Symbol, order.OrderID)
+	default:
+		return fmt.Errorf("unknown market %s", order.Market)
+	}
+}
+
+// managedFromExchangeOrder converts a spot order snapshot
+func managedFromExchangeOrder(symbol string, order ExchangeOrder) ManagedOrder {
+	if order.Symbol != "" {
+		symbol = order.Symbol
+	}
+
+	return ManagedOrder{
+		OrderID:       order.ID,
+		ClientOrderID: order.ClientOrderID,
+		Market:        OrderMarketSpot,
+		Symbol:        symbol,
+		Side:          OrderSide(order.Side.String()),
+		Type:          OrderType(order.Type.String()),
+		Price:         order.Price,
+		Amount:        order.Amount,
+		FilledAmount:  order.FilledAmount,
+		AvgPrice:      order.AvgPrice,
+		Status:        OrderStatus(normalizeOrderStatus(string(order.Status))),
+		CreatedAt:     order.CreatedAt.Time(),
+		UpdatedAt:     order.UpdatedAt.Time(),
+	}
+}
+
+// managedFromFuturesOrder converts a futures order snapshot
+func managedFromFuturesOrder(futuresName string, order FuturesOrder) ManagedOrder {
+	return ManagedOrder{
+		OrderID:       order.OrderID,
+		ClientOrderID: order.ClientOrderID,
+		Market:        OrderMarketFutures,
+		Symbol:        futuresName,
+		Side:          OrderSide(order.Side.String()),
+		Type:          OrderType(order.Type.String()),
+		Price:         order.Price,
+		Amount:        order.Volume,
+		FilledAmount:  order.ExecutedQty,
+		AvgPrice:      order.AvgPrice,
+		Status:        OrderStatus(normalizeOrderStatus(string(order.Status))),
+		CreatedAt:     order.CreatedAt.Time(),
+		UpdatedAt:     order.UpdatedAt.Time(),
+	}
+}
+
+// driftedFields lists the fields in which the exchange state differs from the local state
+func driftedFields(local, remote ManagedOrder) []string {
+	var fields []string
+	if remote.Status != "" && local.Status != remote.Status {
+		fields = append(fields, "status")
+	}
+	if !local.FilledAmount.Equal(remote.FilledAmount) {
+		fields = append(fields, "filledAmount")
+	}
+	if !local.Price.IsZero() && !remote.Price.IsZero() && !local.Price.Equal(remote.Price) {
+		fields = append(fields, "price")
+	}
+	if !local.Amount.IsZero() && !remote.Amount.IsZero() && !local.Amount.Equal(remote.Amount) {
+		fields = append(fields, "amount")
+	}
+	return fields
+}
+
+func sortDiscrepancies(list []OrderDiscrepancy) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Market != list[j].Market {
+			return list[i].Market < list[j].Market
+		}
+		if list[i].Symbol != list[j].Symbol {
+			return list[i].Symbol < list[j].Symbol
+		}
+		return list[i].OrderID < list[j].OrderID
+	})
+}
+
+// stringSet is a set of non-empty strings
+type stringSet map[string]struct{}
+
+func newStringSet(values ...string) stringSet {
+	s := stringSet{}
+	for _, v := range values {
+		s.add(v)
+	}
+	return s
+}
+
+func (s stringSet) add(v string) {
+	if v != "" {
+		s[v] = struct{}{}
+	}
+}
+
+func (s stringSet) sorted() []string {
+	result := make([]string, 0, len(s))
+	for v := range s {
+		result = append(result, v)
+	}
+	sort.Strings(result)
+	return result
+}
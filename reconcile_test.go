@@ -0,0 +1,195 @@
+package byex
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFileOrderJournal(t *testing.T) {
+	journal := NewFileOrderJournal(filepath.Join(t.TempDir(), "orders.json"))
+
+	orders, err := journal.Load()
+	if err != nil {
+		t.Fatalf("Load() of missing journal returned error: %v", err)
+	}
+	if len(orders) != 0 {
+		t.Errorf("Expected empty journal, got %d orders", len(orders))
+	}
+
+	saved := []ManagedOrder{
+		{OrderID: "1", Market: OrderMarketSpot, Symbol: "BTCUSDT", Status: OrderStatusNew, Price: decimal.RequireFromString("45000.5")},
+		{OrderID: "2", Market: OrderMarketFutures, Symbol: "E-BTC-USDT", Status: OrderStatusFilled},
+	}
+	if err := journal.Save(saved); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	orders, err = journal.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(orders) != 2 || orders[0].OrderID != "1" || !orders[0].Price.Equal(saved[0].Price) || orders[1].Market != OrderMarketFutures {
+		t.Errorf("Unexpected journal contents: %+v", orders)
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	var cancelled []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/open/api/v2/new_order":
+			// Two pages of spot open orders
+			if q.Get("page") == "1" {
+				writeMockResponse(w, `{"count":3,"resultList":[{"id":"s1","status":"NEW","price":"100","amount":"1"},{"id":"s2","status":"PART_FILLED","price":"100","amount":"1","filled_amount":"0.5"}]}`)
+			} else {
+				writeMockResponse(w, `{"count":3,"resultList":[{"id":"s9","status":"NEW","price":"90","amount":"2"}]}`)
+			}
+		case "/open/api/v2/my_trades":
+			writeMockResponse(w, `{"count":2,"resultList":[{"id":"t1","order_id":"s3","amount":"1"},{"id":"t2","order_id":"s2","amount":"0.5"}]}`)
+		case "/open/api/cancel_order":
+			r.ParseForm()
+			cancelled = append(cancelled, r.PostForm.Get("order_id"))
+			writeMockResponse(w, `null`)
+		case "/fapi/v1/trade/openOrders":
+			writeMockResponse(w, `[{"orderId":"f1","status":"NEW","price":"200","volume":"5"}]`)
+		case "/fapi/v1/openOrders":
+			writeMockResponse(w, `[{"orderId":"f1","status":"NEW","price":"200","volume":"5"}]`)
+		case "/fapi/v1/userTrades":
+			writeMockResponse(w, `[]`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	journal := NewFileOrderJournal(filepath.Join(t.TempDir(), "orders.json"))
+	journal.Save([]ManagedOrder{
+		{OrderID: "s1", Market: OrderMarketSpot, Symbol: "BTCUSDT", Status: OrderStatusNew, Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)},
+		{OrderID: "s2", Market: OrderMarketSpot, Symbol: "BTCUSDT", Status: OrderStatusNew, Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)},
+		{OrderID: "s3", Market: OrderMarketSpot, Symbol: "BTCUSDT", Status: OrderStatusNew, Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)},
+		{OrderID: "s4", Market: OrderMarketSpot, Symbol: "BTCUSDT", Status: OrderStatusFilled},
+		{OrderID: "f1", Market: OrderMarketFutures, Symbol: "E-BTC-USDT", Status: OrderStatusNew, Price: decimal.NewFromInt(200), Amount: decimal.NewFromInt(5)},
+	})
+
+	reconciler := NewReconciler(client.Exchange(), client.Futures(), journal)
+	report, err := reconciler.Reconcile(ReconcileOptions{PageSize: 2, CancelOrphans: true})
+	if err != nil {
+		t.Fatalf("Reconcile() returned error: %v", err)
+	}
+
+	if report.Matched != 2 {
+		t.Errorf("Expected 2 matched orders (s1, f1), got %d", report.Matched)
+	}
+
+	if len(report.Orphaned) != 1 || report.Orphaned[0].OrderID != "s9" {
+		t.Fatalf("Expected s9 orphaned, got %+v", report.Orphaned)
+	}
+	if !report.Orphaned[0].Cancelled || len(cancelled) != 1 || cancelled[0] != "s9" {
+		t.Errorf("Expected orphan s9 to be cancelled, got %v", cancelled)
+	}
+
+	if len(report.Missing) != 1 || report.Missing[0].OrderID != "s3" {
+		t.Fatalf("Expected s3 missing, got %+v", report.Missing)
+	}
+	if !report.Missing[0].TradedAmount.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected s3 traded amount 1, got %s", report.Missing[0].TradedAmount)
+	}
+
+	if len(report.Drifted) != 1 || report.Drifted[0].OrderID != "s2" {
+		t.Fatalf("Expected s2 drifted, got %+v", report.Drifted)
+	}
+	fields := report.Drifted[0].Fields
+	if len(fields) != 2 || fields[0] != "status" || fields[1] != "filledAmount" {
+		t.Errorf("Expected status and filledAmount drift, got %v", fields)
+	}
+}
+
+func TestReconciler_PagesWithoutCount(t *testing.T) {
+	var cancelled []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/v2/new_order":
+			// No count field: paging must continue until a short page
+			switch r.URL.Query().Get("page") {
+			case "1":
+				writeMockResponse(w, `{"resultList":[{"id":"a1","status":"NEW"},{"id":"a2","status":"NEW"}]}`)
+			case "2":
+				writeMockResponse(w, `{"resultList":[{"id":"a3","status":"NEW"},{"id":"a4","status":"NEW"}]}`)
+			default:
+				writeMockResponse(w, `{"resultList":[{"id":"a5","status":"NEW"}]}`)
+			}
+		case "/open/api/v2/my_trades":
+			writeMockResponse(w, `{"resultList":[]}`)
+		case "/open/api/cancel_order":
+			r.ParseForm()
+			cancelled = append(cancelled, r.PostForm.Get("order_id"))
+			writeMockResponse(w, `null`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	journal := NewFileOrderJournal(filepath.Join(t.TempDir(), "orders.json"))
+	reconciler := NewReconciler(client.Exchange(), nil, journal)
+	report, err := reconciler.Reconcile(ReconcileOptions{Symbols: []string{"BTCUSDT"}, PageSize: 2, CancelOrphans: true})
+	if err != nil {
+		t.Fatalf("Reconcile() returned error: %v", err)
+	}
+
+	if len(report.Orphaned) != 5 || len(cancelled) != 5 {
+		t.Errorf("Expected all 5 orphans across 3 pages to be reported and cancelled, got %d and %v", len(report.Orphaned), cancelled)
+	}
+}
+
+func TestReconciler_PageIgnored(t *testing.T) {
+	requests := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/v2/new_order":
+			// The page parameter is ignored and the same full page comes back without a count
+			requests++
+			writeMockResponse(w, `{"count":0,"resultList":[{"id":"a1","status":"NEW"},{"id":"a2","status":"NEW"}]}`)
+		case "/open/api/v2/my_trades":
+			writeMockResponse(w, `{"resultList":[]}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	orders, err := spotOpenOrders(client.Exchange(), "BTCUSDT", 2)
+	if err != nil {
+		t.Fatalf("spotOpenOrders() returned error: %v", err)
+	}
+	if len(orders) != 2 || requests != 2 {
+		t.Errorf("Expected 2 orders after a repeated page, got %d orders in %d requests", len(orders), requests)
+	}
+}
+
+func TestOrderManager_AutoSave(t *testing.T) {
+	journal := NewFileOrderJournal(filepath.Join(t.TempDir(), "orders.json"))
+
+	m := NewOrderManager(nil, nil)
+	var saveErrs []error
+	m.AutoSave(journal, func(err error) { saveErrs = append(saveErrs, err) })
+
+	m.Track(ManagedOrder{OrderID: "1", Market: OrderMarketSpot, Symbol: "BTCUSDT"})
+	m.Track(ManagedOrder{OrderID: "2", Market: OrderMarketSpot, Symbol: "BTCUSDT"})
+	if err := m.Update(OrderUpdate{Market: OrderMarketSpot, OrderID: "1", Status: "FILLED"}); err != nil {
+		t.Fatalf("Update() returned error: %v", err)
+	}
+	m.Forget(OrderMarketSpot, "2")
+
+	orders, err := journal.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(orders) != 1 || orders[0].OrderID != "1" || orders[0].Status != OrderStatusFilled {
+		t.Errorf("Journal should follow the manager, got %+v", orders)
+	}
+	if len(saveErrs) != 0 {
+		t.Errorf("Unexpected save errors: %v", saveErrs)
+	}
+}
@@ -0,0 +1,452 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrRiskLimit is returned when an order breaks a limit of the RiskGuard
+	ErrRiskLimit = errors.New("risk limit exceeded")
+	// ErrKillSwitch is returned for every new order while the kill switch is active
+	ErrKillSwitch = errors.New("kill switch is active")
+)
+
+// riskOpenOrdersPageSize is the page size used to count spot open orders
+const riskOpenOrdersPageSize = 100
+
+// RiskLimits configures a RiskGuard. A zero value disables its check.
+type RiskLimits struct {
+	// MaxOrderNotional caps price * amount of a single order. Market orders are valued at the last price.
+	MaxOrderNotional decimal.Decimal
+	// MaxPosition caps the position per futures name, in contracts, after an opening order fills
+	MaxPosition map[string]decimal.Decimal
+	// MaxOpenOrders caps the open orders per symbol, counting the new orders
+	MaxOpenOrders int
+	// MaxOrdersPerSecond caps the orders sent in any one-second window
+	MaxOrdersPerSecond int
+	// PriceBand rejects limit orders priced further than this fraction from the last price (0.05 for 5%)
+	PriceBand decimal.Decimal
+	// ContractMultipliers converts futures volumes into base amounts for MaxOrderNotional.
+	// Futures names missing from it use 1.
+	ContractMultipliers map[string]decimal.Decimal
+	// KillSymbols and KillFuturesNames are cancelled by Kill on every client of the
+	// guard, on top of the symbols traded through it. List every symbol that may have
+	// open orders placed outside the guard.
+	KillSymbols      []string
+	KillFuturesNames []string
+}
+
+// RiskGuard runs pre-trade checks on every order placed by the clients it is set on
+// with ClientOption.RiskGuard. It may be shared by several clients.
+type RiskGuard struct {
+	limits RiskLimits
+
+	mu      sync.Mutex
+	killed  bool
+	sent    []time.Time // orders sent during the last second
+	symbols map[*Client]*riskSymbols
+}
+
+// riskReservation is the rate limit taken by orders about to be sent
+type riskReservation struct {
+	guard *RiskGuard
+	at    time.Time
+	count int
+}
+
+// riskSymbols are the symbols a client has traded through the guard
+type riskSymbols struct {
+	spot    stringSet
+	futures stringSet
+}
+
+// riskOrder is an order reduced to what the checks need
+type riskOrder struct {
+	market OrderMarket
+	symbol string
+	side   string
+	// opens is set for futures orders opening or adding to a position
+	opens bool
+	// price is zero for market orders
+	price  decimal.Decimal
+	amount decimal.Decimal
+	// notional is set instead of amount for orders sized in the quote currency
+	notional decimal.Decimal
+}
+
+// NewRiskGuard creates a risk guard enforcing limits
+func NewRiskGuard(limits RiskLimits) *RiskGuard {
+	return &RiskGuard{
+		limits:  limits,
+		symbols: make(map[*Client]*riskSymbols),
+	}
+}
+
+// Kill activates the kill switch. New orders are rejected with ErrKillSwitch until
+// Reset, and on every client of the guard all open orders are cancelled for the
+// symbols traded through the guard and those in KillSymbols and KillFuturesNames.
+// Cancellation keeps going when a symbol fails and returns the joined errors.
+func (g *RiskGuard) Kill() error {
+	g.mu.Lock()
+	g.killed = true
+	type target struct {
+		client  *Client
+		spot    []string
+		futures []string
+	}
+	targets := make([]target, 0, len(g.symbols))
+	for client, symbols := range g.symbols {
+		spot, futures := newStringSet(), newStringSet()
+		for _, symbol := range append(symbols.spot.sorted(), g.limits.KillSymbols...) {
+			spot.add(symbol)
+		}
+		for _, name := range append(symbols.futures.sorted(), g.limits.KillFuturesNames...) {
+			futures.add(name)
+		}
+		targets = append(targets, target{client, spot.sorted(), futures.sorted()})
+	}
+	g.mu.Unlock()
+
+	var errs []error
+	for _, t := range targets {
+		for _, symbol := range t.spot {
+			if err := t.client.Exchange().CancelAllOrders(symbol); err != nil {
+				errs = append(errs, fmt.Errorf("failed to cancel orders for %s: %w", symbol, err))
+			}
+		}
+		for _, name := range t.futures {
+			if err := t.client.Futures().CancelAllOrders(name); err != nil {
+				errs = append(errs, fmt.Errorf("failed to cancel orders for %s: %w", name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reset deactivates the kill switch
+func (g *RiskGuard) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.killed = false
+}
+
+// Killed reports whether the kill switch is active
+func (g *RiskGuard) Killed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.killed
+}
+
+// register adds c to the clients cancelled by Kill
+func (g *RiskGuard) register(c *Client) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.clientSymbols(c)
+}
+
+// clientSymbols returns the symbols traded by c, the caller holds g.mu
+func (g *RiskGuard) clientSymbols(c *Client) *riskSymbols {
+	symbols, ok := g.symbols[c]
+	if !ok {
+		symbols = &riskSymbols{spot: newStringSet(), futures: newStringSet()}
+		g.symbols[c] = symbols
+	}
+	return symbols
+}
+
+// check runs every check on orders about to be sent together by c
+func (g *RiskGuard) check(c *Client, orders []riskOrder) (*riskReservation, error) {
+	if g.Killed() {
+		return nil, ErrKillSwitch
+	}
+
+	lastPrices := make(map[string]decimal.Decimal)
+	for _, order := range orders {
+		if err := g.checkOrder(c, order, lastPrices); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.checkPositions(c, orders); err != nil {
+		return nil, err
+	}
+	if err := g.checkOpenOrders(c, orders); err != nil {
+		return nil, err
+	}
+
+	return g.reserve(c, orders)
+}
+
+// checkOrder checks the notional and price band of a single order
+func (g *RiskGuard) checkOrder(c *Client, order riskOrder, lastPrices map[string]decimal.Decimal) error {
+	checkBand := g.limits.PriceBand.IsPositive() && order.price.IsPositive()
+	checkNotional := g.limits.MaxOrderNotional.IsPositive()
+	if !checkBand && !checkNotional {
+		return nil
+	}
+
+	price := order.price
+	if checkBand || checkNotional && order.notional.IsZero() && price.IsZero() {
+		last, err := g.lastPrice(c, order, lastPrices)
+		if err != nil {
+			return err
+		}
+
+		if checkBand {
+			deviation := order.price.Sub(last).Abs().Div(last)
+			if deviation.GreaterThan(g.limits.PriceBand) {
+				return fmt.Errorf("%w: %s price %s is %s from the last price %s", ErrRiskLimit, order.symbol, order.price, deviation, last)
+			}
+		}
+		if price.IsZero() {
+			price = last
+		}
+	}
+
+	if checkNotional {
+		notional := order.notional
+		if notional.IsZero() {
+			notional = order.amount.Mul(price).Mul(g.multiplier(order))
+		}
+		if notional.GreaterThan(g.limits.MaxOrderNotional) {
+			return fmt.Errorf("%w: %s order notional %s exceeds %s", ErrRiskLimit, order.symbol, notional, g.limits.MaxOrderNotional)
+		}
+	}
+
+	return nil
+}
+
+// lastPrice returns the last traded price of the order's symbol, once per check
+func (g *RiskGuard) lastPrice(c *Client, order riskOrder, cache map[string]decimal.Decimal) (decimal.Decimal, error) {
+	key := string(order.market) + ":" + order.symbol
+	if last, ok := cache[key]; ok {
+		return last, nil
+	}
+
+	var last decimal.Decimal
+	switch order.market {
+	case OrderMarketSpot:
+		ticker, err := c.Exchange().GetTicker(order.symbol)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get last price for risk check: %w", err)
+		}
+		last = ticker.Last
+	case OrderMarketFutures:
+		ticker, err := c.Futures().GetTicker(order.symbol)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get last price for risk check: %w", err)
+		}
+		last = ticker.LastPrice
+	}
+	if !last.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%w: no last price for %s", ErrRiskLimit, order.symbol)
+	}
+
+	cache[key] = last
+	return last, nil
+}
+
+func (g *RiskGuard) multiplier(order riskOrder) decimal.Decimal {
+	if order.market == OrderMarketFutures {
+		if m, ok := g.limits.ContractMultipliers[order.symbol]; ok && m.IsPositive() {
+			return m
+		}
+	}
+	return decimal.NewFromInt(1)
+}
+
+// checkPositions checks that opening futures orders keep each position within MaxPosition
+func (g *RiskGuard) checkPositions(c *Client, orders []riskOrder) error {
+	if len(g.limits.MaxPosition) == 0 {
+		return nil
+	}
+
+	// Added volume per futures name and side
+	added := make(map[[2]string]decimal.Decimal)
+	var keys [][2]string
+	for _, order := range orders {
+		if !order.opens {
+			continue
+		}
+		if _, ok := g.limits.MaxPosition[order.symbol]; !ok {
+			continue
+		}
+		key := [2]string{order.symbol, strings.ToUpper(order.side)}
+		if _, ok := added[key]; !ok {
+			keys = append(keys, key)
+		}
+		added[key] = added[key].Add(order.amount)
+	}
+
+	for _, key := range keys {
+		positions, err := c.Futures().GetPositions(key[0])
+		if err != nil {
+			return fmt.Errorf("failed to get positions for risk check: %w", err)
+		}
+
+		long := key[1] == OrderSideBuy
+		size := added[key]
+		for _, position := range positions {
+			if isLongPosition(position) == long {
+				size = size.Add(position.PositionAmt.Abs())
+			}
+		}
+
+		if max := g.limits.MaxPosition[key[0]]; size.GreaterThan(max) {
+			return fmt.Errorf("%w: %s position would reach %s, above %s", ErrRiskLimit, key[0], size, max)
+		}
+	}
+
+	return nil
+}
+
+// checkOpenOrders checks that each symbol stays within MaxOpenOrders
+func (g *RiskGuard) checkOpenOrders(c *Client, orders []riskOrder) error {
+	if g.limits.MaxOpenOrders <= 0 {
+		return nil
+	}
+
+	added := make(map[riskSymbolKey]int)
+	var keys []riskSymbolKey
+	for _, order := range orders {
+		key := riskSymbolKey{order.market, order.symbol}
+		if _, ok := added[key]; !ok {
+			keys = append(keys, key)
+		}
+		added[key]++
+	}
+
+	for _, key := range keys {
+		var (
+			open []ManagedOrder
+			err  error
+		)
+		switch key.market {
+		case OrderMarketSpot:
+			open, err = spotOpenOrders(c.Exchange(), key.symbol, riskOpenOrdersPageSize)
+		case OrderMarketFutures:
+			open, err = futuresOpenOrders(c.Futures(), key.symbol)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to count open orders for risk check: %w", err)
+		}
+
+		if n := len(open) + added[key]; n > g.limits.MaxOpenOrders {
+			return fmt.Errorf("%w: %s would have %d open orders, above %d", ErrRiskLimit, key.symbol, n, g.limits.MaxOpenOrders)
+		}
+	}
+
+	return nil
+}
+
+type riskSymbolKey struct {
+	market OrderMarket
+	symbol string
+}
+
+// reserve applies the order rate limit and records the symbols of orders about to be sent
+func (g *RiskGuard) reserve(c *Client, orders []riskOrder) (*riskReservation, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// The kill switch may have been activated while the checks ran
+	if g.killed {
+		return nil, ErrKillSwitch
+	}
+
+	var reservation *riskReservation
+
+	if max := g.limits.MaxOrdersPerSecond; max > 0 {
+		now := time.Now()
+		cutoff := now.Add(-time.Second)
+		recent := g.sent[:0]
+		for _, t := range g.sent {
+			if t.After(cutoff) {
+				recent = append(recent, t)
+			}
+		}
+		g.sent = recent
+
+		if len(g.sent)+len(orders) > max {
+			return nil, fmt.Errorf("%w: more than %d orders per second", ErrRiskLimit, max)
+		}
+		for range orders {
+			g.sent = append(g.sent, now)
+		}
+		reservation = &riskReservation{guard: g, at: now, count: len(orders)}
+	}
+
+	symbols := g.clientSymbols(c)
+	for _, order := range orders {
+		switch order.market {
+		case OrderMarketSpot:
+			symbols.spot.add(order.symbol)
+		case OrderMarketFutures:
+			symbols.futures.add(order.symbol)
+		}
+	}
+
+	return reservation, nil
+}
+
+// release gives the rate limit back when sending the orders failed with err.
+// Orders whose outcome is unknown may have reached the exchange and keep it.
+func (r *riskReservation) release(err error) {
+	if r == nil || err == nil || isOutcomeUnknown(err) {
+		return
+	}
+
+	g := r.guard
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	kept := g.sent[:0]
+	n := r.count
+	for _, t := range g.sent {
+		if n > 0 && t.Equal(r.at) {
+			n--
+			continue
+		}
+		kept = append(kept, t)
+	}
+	g.sent = kept
+}
+
+// checkRisk runs the checks of the client's risk guard, if any.
+// The returned reservation is nil without a rate limit.
+func (c *Client) checkRisk(orders ...riskOrder) (*riskReservation, error) {
+	if c.riskGuard == nil || len(orders) == 0 {
+		return nil, nil
+	}
+	return c.riskGuard.check(c, orders)
+}
+
+// spotRiskOrder describes a spot order for the risk checks
+func spotRiskOrder(symbol string, side OrderSide, orderType OrderType, price, amount decimal.Decimal) riskOrder {
+	order := riskOrder{market: OrderMarketSpot, symbol: symbol, side: string(side), amount: amount}
+	if !strings.EqualFold(string(orderType), OrderTypeMarket) {
+		order.price = price
+	}
+	return order
+}
+
+// futuresRiskOrder describes a futures order for the risk checks
+func futuresRiskOrder(futuresName string, side OrderSide, orderType OrderType, open string, price, volume decimal.Decimal) riskOrder {
+	order := riskOrder{
+		market: OrderMarketFutures,
+		symbol: futuresName,
+		side:   string(side),
+		opens:  strings.EqualFold(open, FuturesTradeTypeOpen),
+		amount: volume,
+	}
+	if !strings.EqualFold(string(orderType), OrderTypeMarket) {
+		order.price = price
+	}
+	return order
+}
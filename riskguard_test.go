@@ -0,0 +1,186 @@
+package byex
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// newRiskMockClient answers the market data and order endpoints used by the risk tests
+func newRiskMockClient(t *testing.T, guard *RiskGuard, cancelled *[]string) *Client {
+	var mu sync.Mutex
+	return newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/get_ticker":
+			writeMockResponse(w, `{"symbol":"BTCUSDT","last":"100"}`)
+		case "/fapi/v1/ticker":
+			writeMockResponse(w, `{"symbol":"E-BTC-USDT","lastPrice":"100"}`)
+		case "/open/api/v2/new_order":
+			writeMockResponse(w, `{"count":2,"resultList":[{"id":"1"},{"id":"2"}]}`)
+		case "/fapi/v1/position/positions":
+			writeMockResponse(w, `[{"symbol":"E-BTC-USDT","positionSide":"LONG","positionAmt":"8"}]`)
+		case "/open/api/create_order", "/fapi/v1/trade/order":
+			writeMockResponse(w, `{"orderId":"1"}`)
+		case "/open/api/cancel_order_all", "/fapi/v1/trade/cancelAll":
+			mu.Lock()
+			*cancelled = append(*cancelled, r.URL.Path)
+			mu.Unlock()
+			writeMockResponse(w, `null`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}, ClientOption{Testnet: true, RiskGuard: guard})
+}
+
+func TestRiskGuard_NotionalAndPriceBand(t *testing.T) {
+	guard := NewRiskGuard(RiskLimits{
+		MaxOrderNotional: decimal.NewFromInt(1000),
+		PriceBand:        decimal.RequireFromString("0.05"),
+	})
+	exchange := newRiskMockClient(t, guard, &[]string{}).Exchange()
+
+	tests := []struct {
+		name    string
+		req     CreateOrderRequest
+		wantErr bool
+	}{
+		{"within limits", CreateOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromInt(101), Amount: decimal.NewFromInt(5)}, false},
+		{"notional too large", CreateOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(20)}, true},
+		{"outside price band", CreateOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromInt(110), Amount: decimal.NewFromInt(1)}, true},
+		{"market valued at last price", CreateOrderRequest{Type: OrderTypeMarket, Amount: decimal.NewFromInt(5)}, false},
+		{"large market order", CreateOrderRequest{Type: OrderTypeMarket, Amount: decimal.NewFromInt(11)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Symbol = "BTCUSDT"
+			tt.req.Side = OrderSideBuy
+			_, err := exchange.CreateOrder(tt.req)
+			if tt.wantErr && !errors.Is(err, ErrRiskLimit) {
+				t.Errorf("Expected ErrRiskLimit, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRiskGuard_OrderRateAndOpenOrders(t *testing.T) {
+	order := CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)}
+
+	rateLimited := newRiskMockClient(t, NewRiskGuard(RiskLimits{MaxOrdersPerSecond: 2}), &[]string{}).Exchange()
+	for i := 0; i < 2; i++ {
+		if _, err := rateLimited.CreateOrder(order); err != nil {
+			t.Fatalf("CreateOrder() returned error: %v", err)
+		}
+	}
+	if _, err := rateLimited.CreateOrder(order); !errors.Is(err, ErrRiskLimit) {
+		t.Errorf("Expected the third order in a second to be rejected, got %v", err)
+	}
+
+	// The mock reports 2 open orders already
+	capped := newRiskMockClient(t, NewRiskGuard(RiskLimits{MaxOpenOrders: 2}), &[]string{}).Exchange()
+	if _, err := capped.CreateOrder(order); !errors.Is(err, ErrRiskLimit) {
+		t.Errorf("Expected the open order cap to reject the order, got %v", err)
+	}
+}
+
+func TestRiskGuard_MaxPosition(t *testing.T) {
+	guard := NewRiskGuard(RiskLimits{MaxPosition: map[string]decimal.Decimal{"E-BTC-USDT": decimal.NewFromInt(10)}})
+	futures := newRiskMockClient(t, guard, &[]string{}).Futures()
+
+	order := FuturesCreateOrderRequest{
+		FuturesName: "E-BTC-USDT",
+		Type:        OrderTypeMarket,
+		Volume:      decimal.NewFromInt(3),
+	}
+
+	// Long 8 + 3 is above 10
+	order.Side, order.Open = OrderSideBuy, FuturesTradeTypeOpen
+	if _, err := futures.CreateOrder(order); !errors.Is(err, ErrRiskLimit) {
+		t.Errorf("Expected the long position cap to reject the order, got %v", err)
+	}
+
+	order.Side, order.Open = OrderSideSell, FuturesTradeTypeClose
+	if _, err := futures.CreateOrder(order); err != nil {
+		t.Errorf("Expected closing order to pass, got %v", err)
+	}
+
+	order.Side, order.Open = OrderSideSell, FuturesTradeTypeOpen
+	if _, err := futures.CreateOrder(order); err != nil {
+		t.Errorf("Expected short order to pass, got %v", err)
+	}
+}
+
+func TestRiskGuard_Kill(t *testing.T) {
+	var cancelled []string
+	guard := NewRiskGuard(RiskLimits{})
+	client := newRiskMockClient(t, guard, &cancelled)
+
+	if _, err := client.Exchange().CreateOrder(CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}); err != nil {
+		t.Fatalf("CreateOrder() returned error: %v", err)
+	}
+	if _, err := client.Futures().CreateOrder(FuturesCreateOrderRequest{FuturesName: "E-BTC-USDT", Side: OrderSideBuy, Type: OrderTypeMarket, Volume: decimal.NewFromInt(1)}); err != nil {
+		t.Fatalf("CreateOrder() returned error: %v", err)
+	}
+
+	if err := guard.Kill(); err != nil {
+		t.Fatalf("Kill() returned error: %v", err)
+	}
+	if len(cancelled) != 2 {
+		t.Errorf("Expected orders cancelled for both traded symbols, got %v", cancelled)
+	}
+
+	if _, err := client.Exchange().CreateOrder(CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}); !errors.Is(err, ErrKillSwitch) {
+		t.Errorf("Expected ErrKillSwitch, got %v", err)
+	}
+
+	guard.Reset()
+	if _, err := client.Exchange().CreateOrder(CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}); err != nil {
+		t.Errorf("Expected orders to pass after Reset, got %v", err)
+	}
+}
+
+func TestRiskGuard_KillConfiguredSymbols(t *testing.T) {
+	var cancelled []string
+	guard := NewRiskGuard(RiskLimits{
+		KillSymbols:      []string{"BTCUSDT", "ETHUSDT"},
+		KillFuturesNames: []string{"E-BTC-USDT"},
+	})
+	newRiskMockClient(t, guard, &cancelled)
+
+	// Nothing was traded through the guard, the configured symbols are still cancelled
+	if err := guard.Kill(); err != nil {
+		t.Fatalf("Kill() returned error: %v", err)
+	}
+	if len(cancelled) != 3 {
+		t.Errorf("Expected orders cancelled for the 3 configured symbols, got %v", cancelled)
+	}
+}
+
+func TestRiskGuard_OrderRateReleasedOnFailure(t *testing.T) {
+	var calls int
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Write([]byte(`{"code":"10004","msg":"symbol not found"}`))
+			return
+		}
+		writeMockResponse(w, `{"orderId":"1"}`)
+	}, ClientOption{Testnet: true, RiskGuard: NewRiskGuard(RiskLimits{MaxOrdersPerSecond: 1})})
+
+	order := CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}
+	if _, err := client.Exchange().CreateOrder(order); err == nil {
+		t.Fatal("Expected the rejected order to return an error")
+	}
+	if _, err := client.Exchange().CreateOrder(order); err != nil {
+		t.Errorf("Expected the rejected order to give its rate limit back, got %v", err)
+	}
+	if _, err := client.Exchange().CreateOrder(order); !errors.Is(err, ErrRiskLimit) {
+		t.Errorf("Expected the second sent order in a second to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package byex
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// SchemaDrift describes a response object whose fields differ from its Go type
+type SchemaDrift struct {
+	Type       string   // Go type the object was decoded into, e.g. "ExchangeOrder"
+	Unexpected []string // fields of the response the type has no field for
+	Missing    []string // fields of the type the response did not send
+}
+
+// SchemaDriftHandler is called for every decoded response object with unexpected or missing fields
+type SchemaDriftHandler func(SchemaDrift)
+
+var (
+	schemaDriftHandler atomic.Value // SchemaDriftHandler
+	keepRawJSON        atomic.Bool
+)
+
+// SetSchemaDriftHandler installs h for all clients. A nil handler turns reporting off.
+// The handler is called from the decoding goroutine, so it should return quickly.
+// Responses decoded while a handler is installed also keep Raw and Extra.
+func SetSchemaDriftHandler(h SchemaDriftHandler) {
+	schemaDriftHandler.Store(h)
+}
+
+// SetKeepRawJSON makes response types keep the JSON they were decoded from, for Raw and
+// Extra, for all clients. It is off by default, as it decodes every object twice.
+func SetKeepRawJSON(keep bool) {
+	keepRawJSON.Store(keep)
+}
+
+// rawFields keeps the JSON object a response type was decoded from. The object is held
+// behind a pointer so the response types stay comparable.
+type rawFields struct {
+	raw *rawObject
+}
+
+type rawObject struct {
+	data  json.RawMessage
+	extra map[string]json.RawMessage
+}
+
+// Raw returns the JSON object the value was decoded from. It is nil when the value was
+// built in code, or decoded without SetKeepRawJSON or a SchemaDriftHandler.
+func (r rawFields) Raw() json.RawMessage {
+	if r.raw == nil {
+		return nil
+	}
+	return r.raw.data
+}
+
+// Extra returns the fields of the response that have no struct field, or nil when there
+// are none or the raw JSON was not kept
+func (r rawFields) Extra() map[string]json.RawMessage {
+	if r.raw == nil {
+		return nil
+	}
+	return r.raw.extra
+}
+
+// unmarshalObject decodes data into v, a version of the named response type without its
+// UnmarshalJSON method. With SetKeepRawJSON or a drift handler, it also records the raw
+// object and its unknown fields in raw and reports drift.
+func unmarshalObject(name string, data []byte, v interface{}, raw *rawFields) error {
+	raw.raw = nil
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	h, _ := schemaDriftHandler.Load().(SchemaDriftHandler)
+	if h == nil && !keepRawJSON.Load() {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil || fields == nil {
+		return nil
+	}
+
+	obj := &rawObject{data: append(json.RawMessage(nil), data...)}
+	raw.raw = obj
+
+	// encoding/json matches keys case-insensitively, so the checks do too
+	known := jsonFields(reflect.TypeOf(v).Elem())
+	received := make(map[string]bool, len(fields))
+	var drift SchemaDrift
+	for key, value := range fields {
+		received[strings.ToLower(key)] = true
+		if known[strings.ToLower(key)] != "" {
+			continue
+		}
+
+		if obj.extra == nil {
+			obj.extra = make(map[string]json.RawMessage)
+		}
+		obj.extra[key] = value
+		drift.Unexpected = append(drift.Unexpected, key)
+	}
+
+	if h == nil {
+		return nil
+	}
+
+	for lower, field := range known {
+		if !received[lower] {
+			drift.Missing = append(drift.Missing, field)
+		}
+	}
+	if len(drift.Unexpected) == 0 && len(drift.Missing) == 0 {
+		return nil
+	}
+
+	drift.Type = name
+	sort.Strings(drift.Unexpected)
+	sort.Strings(drift.Missing)
+	h(drift)
+	return nil
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> map[string]string
+
+// jsonFields returns the JSON field names of struct type t, keyed by their lower case form
+func jsonFields(t reflect.Type) map[string]string {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.(map[string]string)
+	}
+
+	fields := make(map[string]string)
+	collectJSONFields(t, fields)
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+func collectJSONFields(t reflect.Type, fields map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && tag == "" && ft.Kind() == reflect.Struct {
+			collectJSONFields(ft, fields)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = name
+	}
+}
+
+// UnmarshalJSON implementations of the response types. Each decodes through a local type
+// without the method and keeps the raw object for Raw and Extra.
+
+func (o *ExchangeOrder) UnmarshalJSON(data []byte) error {
+	type plain ExchangeOrder
+	return unmarshalObject("ExchangeOrder", data, (*plain)(o), &o.rawFields)
+}
+
+func (t *ExchangeTrade) UnmarshalJSON(data []byte) error {
+	type plain ExchangeTrade
+	return unmarshalObject("ExchangeTrade", data, (*plain)(t), &t.rawFields)
+}
+
+func (t *ExchangeTicker) UnmarshalJSON(data []byte) error {
+	type plain ExchangeTicker
+	return unmarshalObject("ExchangeTicker", data, (*plain)(t), &t.rawFields)
+}
+
+func (k *ExchangeKline) UnmarshalJSON(data []byte) error {
+	type plain ExchangeKline
+	return unmarshalObject("ExchangeKline", data, (*plain)(k), &k.rawFields)
+}
+
+func (a *ExchangeAccount) UnmarshalJSON(data []byte) error {
+	type plain ExchangeAccount
+	return unmarshalObject("ExchangeAccount", data, (*plain)(a), &a.rawFields)
+}
+
+func (b *CoinBalance) UnmarshalJSON(data []byte) error {
+	type plain CoinBalance
+	return unmarshalObject("CoinBalance", data, (*plain)(b), &b.rawFields)
+}
+
+func (d *ExchangeOrderDetail) UnmarshalJSON(data []byte) error {
+	// The embedded order has its own UnmarshalJSON, which would otherwise decode the whole detail
+	type order ExchangeOrder
+	v := struct {
+		*order
+		Trades *[]ExchangeTrade `json:"trades"`
+	}{(*order)(&d.ExchangeOrder), &d.Trades}
+	return unmarshalObject("ExchangeOrderDetail", data, &v, &d.rawFields)
+}
+
+func (o *FuturesOrder) UnmarshalJSON(data []byte) error {
+	type plain FuturesOrder
+	return unmarshalObject("FuturesOrder", data, (*plain)(o), &o.rawFields)
+}
+
+func (t *FuturesTrade) UnmarshalJSON(data []byte) error {
+	type plain FuturesTrade
+	return unmarshalObject("FuturesTrade", data, (*plain)(t), &t.rawFields)
+}
+
+func (p *FuturesPosition) UnmarshalJSON(data []byte) error {
+	type plain FuturesPosition
+	return unmarshalObject("FuturesPosition", data, (*plain)(p), &p.rawFields)
+}
+
+func (a *FuturesAccount) UnmarshalJSON(data []byte) error {
+	type plain FuturesAccount
+	return unmarshalObject("FuturesAccount", data, (*plain)(a), &a.rawFields)
+}
+
+func (t *FuturesTicker) UnmarshalJSON(data []byte) error {
+	type plain FuturesTicker
+	return unmarshalObject("FuturesTicker", data, (*plain)(t), &t.rawFields)
+}
+
+func (o *FuturesConditionOrder) UnmarshalJSON(data []byte) error {
+	type plain FuturesConditionOrder
+	return unmarshalObject("FuturesConditionOrder", data, (*plain)(o), &o.rawFields)
+}
+
+func (r *FuturesPositionTPSLResponse) UnmarshalJSON(data []byte) error {
+	type plain FuturesPositionTPSLResponse
+	return unmarshalObject("FuturesPositionTPSLResponse", data, (*plain)(r), &r.rawFields)
+}
+
+func (d *FuturesOrderDetail) UnmarshalJSON(data []byte) error {
+	type order FuturesOrder
+	v := struct {
+		*order
+		Trades *[]FuturesTrade `json:"trades"`
+	}{(*order)(&d.FuturesOrder), &d.Trades}
+	return unmarshalObject("FuturesOrderDetail", data, &v, &d.rawFields)
+}
+
+func (p *FuturesIndexPrice) UnmarshalJSON(data []byte) error {
+	type plain FuturesIndexPrice
+	return unmarshalObject("FuturesIndexPrice", data, (*plain)(p), &p.rawFields)
+}
+
+func (c *FuturesCapital) UnmarshalJSON(data []byte) error {
+	type plain FuturesCapital
+	return unmarshalObject("FuturesCapital", data, (*plain)(c), &c.rawFields)
+}
+
+func (r *OrderResponse) UnmarshalJSON(data []byte) error {
+	type plain OrderResponse
+	return unmarshalObject("OrderResponse", data, (*plain)(r), &r.rawFields)
+}
+
+func (c *SymbolCharge) UnmarshalJSON(data []byte) error {
+	type plain SymbolCharge
+	return unmarshalObject("SymbolCharge", data, (*plain)(c), &c.rawFields)
+}
+
+func (b *LeverageFinanceBalance) UnmarshalJSON(data []byte) error {
+	type plain LeverageFinanceBalance
+	return unmarshalObject("LeverageFinanceBalance", data, (*plain)(b), &b.rawFields)
+}
+
+func (d *ExchangeDepth) UnmarshalJSON(data []byte) error {
+	type plain ExchangeDepth
+	return unmarshalObject("ExchangeDepth", data, (*plain)(d), &d.rawFields)
+}
+
+func (r *TickerListResponse) UnmarshalJSON(data []byte) error {
+	type plain TickerListResponse
+	return unmarshalObject("TickerListResponse", data, (*plain)(r), &r.rawFields)
+}
+
+func (r *OrderListResponse) UnmarshalJSON(data []byte) error {
+	type plain OrderListResponse
+	return unmarshalObject("OrderListResponse", data, (*plain)(r), &r.rawFields)
+}
+
+func (r *TradeListResponse) UnmarshalJSON(data []byte) error {
+	type plain TradeListResponse
+	return unmarshalObject("TradeListResponse", data, (*plain)(r), &r.rawFields)
+}
+
+func (r *BatchOrderResponse) UnmarshalJSON(data []byte) error {
+	type plain BatchOrderResponse
+	return unmarshalObject("BatchOrderResponse", data, (*plain)(r), &r.rawFields)
+}
@@ -0,0 +1,153 @@
+package byex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRawFields(t *testing.T) {
+	data := `{"id":"1","symbol":"btcusdt","price":"48000","STATUS":2,"stop_price":"47000","tags":["vip"]}`
+
+	// The raw JSON is only kept on request
+	var order ExchangeOrder
+	if err := json.Unmarshal([]byte(data), &order); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if order.Raw() != nil || order.Extra() != nil {
+		t.Errorf("Expected no raw data by default, got %s", order.Raw())
+	}
+	// Response types stay comparable
+	var a, b OrderResponse
+	json.Unmarshal([]byte(`{"orderId":"1","extra":true}`), &a)
+	json.Unmarshal([]byte(`{"orderId":"1","extra":true}`), &b)
+	if a != b {
+		t.Error("Expected responses decoded from the same JSON to be equal")
+	}
+
+	SetKeepRawJSON(true)
+	t.Cleanup(func() { SetKeepRawJSON(false) })
+
+	if err := json.Unmarshal([]byte(data), &order); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if order.ID != "1" || order.Price.String() != "48000" || order.Status.String() != OrderStatusFilled {
+		t.Errorf("Unexpected order %+v", order)
+	}
+	if string(order.Raw()) != data {
+		t.Errorf("Expected raw %s, got %s", data, order.Raw())
+	}
+	if len(order.Extra()) != 2 || string(order.Extra()["stop_price"]) != `"47000"` || string(order.Extra()["tags"]) != `["vip"]` {
+		t.Errorf("Unexpected extra fields %v", order.Extra())
+	}
+
+	// Extra fields are not written back, the struct encodes as before
+	out, _ := json.Marshal(order)
+	var decoded map[string]interface{}
+	json.Unmarshal(out, &decoded)
+	if _, ok := decoded["stop_price"]; ok {
+		t.Errorf("Expected extra fields to be left out of Marshal, got %s", out)
+	}
+
+	var built ExchangeOrder
+	if built.Raw() != nil || built.Extra() != nil {
+		t.Error("Expected no raw data for an order built in code")
+	}
+}
+
+func TestRawFields_Detail(t *testing.T) {
+	SetKeepRawJSON(true)
+	t.Cleanup(func() { SetKeepRawJSON(false) })
+
+	var detail ExchangeOrderDetail
+	data := `{"id":"1","amount":"2","trades":[{"id":"t1","price":"48000","liquidity":"maker"}],"rebate":"0.1"}`
+	if err := json.Unmarshal([]byte(data), &detail); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if detail.ID != "1" || detail.Amount.String() != "2" || len(detail.Trades) != 1 || detail.Trades[0].ID != "t1" {
+		t.Errorf("Unexpected detail %+v", detail)
+	}
+	if string(detail.Raw()) != data || len(detail.Extra()) != 1 || detail.Extra()["rebate"] == nil {
+		t.Errorf("Unexpected detail raw %s, extra %v", detail.Raw(), detail.Extra())
+	}
+	if detail.Trades[0].Extra()["liquidity"] == nil {
+		t.Errorf("Expected the trade to keep its own extra fields, got %v", detail.Trades[0].Extra())
+	}
+
+	var list OrderListResponse
+	json.Unmarshal([]byte(`{"count":1,"resultList":[{"id":"1"}],"pageSize":100}`), &list)
+	if len(list.ResultList) != 1 || list.Extra()["pageSize"] == nil {
+		t.Errorf("Expected list responses to keep extra fields, got %v", list.Extra())
+	}
+}
+
+func TestSetSchemaDriftHandler(t *testing.T) {
+	var drifts []SchemaDrift
+	SetSchemaDriftHandler(func(d SchemaDrift) { drifts = append(drifts, d) })
+	t.Cleanup(func() { SetSchemaDriftHandler(nil) })
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeMockResponse(w, `{"indexPrice":"48000","markPrice":"48010","time":1640995200000,"symbol":"E-BTC-USDT","fundingRate":"0.0001"}`)
+	})
+	price, err := CallFutures[FuturesIndexPrice](client, "GET", "/fapi/v1/index", nil)
+	if err != nil {
+		t.Fatalf("CallFutures returned error: %v", err)
+	}
+	if price.Extra()["fundingRate"] == nil {
+		t.Errorf("Expected fundingRate in extra fields, got %v", price.Extra())
+	}
+	if len(drifts) != 1 || drifts[0].Type != "FuturesIndexPrice" || !reflect.DeepEqual(drifts[0].Unexpected, []string{"fundingRate"}) || drifts[0].Missing != nil {
+		t.Errorf("Unexpected drift %+v", drifts)
+	}
+
+	drifts = nil
+	json.Unmarshal([]byte(`{"symbol":"E-BTC-USDT","indexPrice":"48000","markPrice":"48010","time":1640995200000}`), &price)
+	if len(drifts) != 0 {
+		t.Errorf("Expected no drift for a matching object, got %v", drifts)
+	}
+
+	json.Unmarshal([]byte(`{"symbol":"E-BTC-USDT","indexPrice":"48000","time":1640995200000,"fundingRate":"0.0001"}`), &price)
+	want := SchemaDrift{Type: "FuturesIndexPrice", Unexpected: []string{"fundingRate"}, Missing: []string{"markPrice"}}
+	if len(drifts) != 1 || !reflect.DeepEqual(drifts[0], want) {
+		t.Errorf("Expected %+v, got %+v", want, drifts)
+	}
+
+	SetSchemaDriftHandler(nil)
+	json.Unmarshal([]byte(`{"fundingRate":"0.0001"}`), &price)
+	if len(drifts) != 1 {
+		t.Errorf("Expected no calls after the handler was removed, got %d", len(drifts))
+	}
+}
+
+func BenchmarkDecodeKlines(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i := 0; i < 500; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, `{"time":%d,"open":"48000.1","high":"48020.3","low":"47990.4","close":"48010.2","volume":"12.5"}`, 1640995200000+i*60000)
+	}
+	buf.WriteString("]")
+	data := buf.Bytes()
+
+	decode := func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var klines []ExchangeKline
+			if err := json.Unmarshal(data, &klines); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("default", decode)
+	b.Run("keepRaw", func(b *testing.B) {
+		SetKeepRawJSON(true)
+		defer SetKeepRawJSON(false)
+		decode(b)
+	})
+}
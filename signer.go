@@ -0,0 +1,63 @@
+package byex
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Signer signs API requests on behalf of the client.
+//
+// Implementations may keep the secret key in memory, or forward the payload
+// to a separate signing process, HSM or KMS so the secret never reaches the
+// trading process.
+type Signer interface {
+	// SignExchange returns the MD5 signature used by exchange APIs.
+	// payload is the sorted parameter string without the secret key appended.
+	SignExchange(payload string) (string, error)
+
+	// SignFutures returns the HMAC-SHA256 signature used by futures APIs.
+	// message is the timestamp, method, path and query/body to be signed.
+	SignFutures(message string) (string, error)
+}
+
+// SignerFunc adapts a pair of functions to the Signer interface
+type SignerFunc struct {
+	Exchange func(payload string) (string, error)
+	Futures  func(message string) (string, error)
+}
+
+// SignExchange calls s.Exchange
+func (s SignerFunc) SignExchange(payload string) (string, error) {
+	return s.Exchange(payload)
+}
+
+// SignFutures calls s.Futures
+func (s SignerFunc) SignFutures(message string) (string, error) {
+	return s.Futures(message)
+}
+
+// SecretSigner signs requests with a secret key held in memory
+type SecretSigner struct {
+	secretKey string
+}
+
+// NewSecretSigner creates a signer that holds secretKey in memory
+func NewSecretSigner(secretKey string) *SecretSigner {
+	return &SecretSigner{secretKey: secretKey}
+}
+
+// SignExchange returns the hex encoded MD5 of payload followed by the secret key
+func (s *SecretSigner) SignExchange(payload string) (string, error) {
+	hash := md5.New()
+	hash.Write([]byte(payload + s.secretKey))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// SignFutures returns the hex encoded HMAC-SHA256 of message keyed by the secret key
+func (s *SecretSigner) SignFutures(message string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(s.secretKey))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,134 @@
+package byex
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestSecretSigner_SignExchange(t *testing.T) {
+	signer := NewSecretSigner(testSecretKey)
+
+	payload := "api_keytest_api_keysymbolBTCUSDTtime1640995200000"
+	signature, err := signer.SignExchange(payload)
+	if err != nil {
+		t.Fatalf("SignExchange() returned error: %v", err)
+	}
+
+	sum := md5.Sum([]byte(payload + testSecretKey))
+	if expected := hex.EncodeToString(sum[:]); signature != expected {
+		t.Errorf("Expected signature %s, got %s", expected, signature)
+	}
+}
+
+func TestSecretSigner_SignFutures(t *testing.T) {
+	signer := NewSecretSigner(testSecretKey)
+
+	message := "1640995200000GET/fapi/v1/ticker?symbol=BTCUSDT"
+	signature, err := signer.SignFutures(message)
+	if err != nil {
+		t.Fatalf("SignFutures() returned error: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(testSecretKey))
+	mac.Write([]byte(message))
+	if expected := hex.EncodeToString(mac.Sum(nil)); signature != expected {
+		t.Errorf("Expected signature %s, got %s", expected, signature)
+	}
+}
+
+func TestClient_DefaultSigner(t *testing.T) {
+	client := NewClient(testApiKey, testSecretKey)
+
+	signer, ok := client.signerFor(Credentials{APIKey: testApiKey, SecretKey: testSecretKey}).(*SecretSigner)
+	if !ok {
+		t.Fatalf("Expected default signer to be *SecretSigner, got %T", signer)
+	}
+	if signer.secretKey != testSecretKey {
+		t.Errorf("Expected default signer to use the client secret key")
+	}
+}
+
+func TestClient_CustomSigner(t *testing.T) {
+	var exchangePayload, futuresMessage string
+	signer := SignerFunc{
+		Exchange: func(payload string) (string, error) {
+			exchangePayload = payload
+			return "exchange-signature", nil
+		},
+		Futures: func(message string) (string, error) {
+			futuresMessage = message
+			return "futures-signature", nil
+		},
+	}
+
+	var gotSign, gotHeader string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotSign = r.URL.Query().Get("sign")
+		gotHeader = r.Header.Get("X-CH-SIGN")
+		writeMockResponse(w, `{}`)
+	}, ClientOption{Signer: signer})
+
+	if _, err := client.Exchange().GetTicker("BTCUSDT"); err != nil {
+		t.Fatalf("GetTicker() returned error: %v", err)
+	}
+	if gotSign != "exchange-signature" {
+		t.Errorf("Expected sign parameter from custom signer, got %q", gotSign)
+	}
+	if exchangePayload == "" {
+		t.Error("Custom signer should receive the exchange payload")
+	}
+
+	if _, err := client.Futures().GetTicker("E-BTC-USDT"); err != nil {
+		t.Fatalf("GetTicker() returned error: %v", err)
+	}
+	if gotHeader != "futures-signature" {
+		t.Errorf("Expected X-CH-SIGN header from custom signer, got %q", gotHeader)
+	}
+	if futuresMessage == "" {
+		t.Error("Custom signer should receive the futures message")
+	}
+}
+
+func TestClient_CustomSignerDropsSecret(t *testing.T) {
+	client := NewClient(testApiKey, testSecretKey, ClientOption{Signer: NewSecretSigner("elsewhere")})
+
+	creds, err := client.loadCredentials()
+	if err != nil {
+		t.Fatalf("loadCredentials() returned error: %v", err)
+	}
+	if creds.APIKey != testApiKey {
+		t.Errorf("Expected apiKey %s, got %s", testApiKey, creds.APIKey)
+	}
+	if creds.SecretKey != "" {
+		t.Error("Secret key should not be kept when a custom signer is set")
+	}
+}
+
+func TestClient_SignerError(t *testing.T) {
+	signErr := errors.New("signing service unavailable")
+	signer := SignerFunc{
+		Exchange: func(string) (string, error) { return "", signErr },
+		Futures:  func(string) (string, error) { return "", signErr },
+	}
+
+	called := false
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		writeMockResponse(w, `{}`)
+	}, ClientOption{Signer: signer})
+
+	if _, err := client.Exchange().GetTicker("BTCUSDT"); !errors.Is(err, signErr) {
+		t.Errorf("Expected signer error, got %v", err)
+	}
+	if _, err := client.Futures().GetTicker("E-BTC-USDT"); !errors.Is(err, signErr) {
+		t.Errorf("Expected signer error, got %v", err)
+	}
+	if called {
+		t.Error("Request should not be sent when signing fails")
+	}
+}
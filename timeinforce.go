@@ -0,0 +1,129 @@
+package byex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrInvalidTimeInForce is returned for an unknown TimeInForce value
+	ErrInvalidTimeInForce = errors.New("invalid time in force")
+	// ErrPostOnlyWouldCross is returned when a post-only order would take liquidity
+	ErrPostOnlyWouldCross = errors.New("post-only order would cross the book")
+	// ErrFillOrKillUnfillable is returned when the book cannot fill a fill-or-kill order completely
+	ErrFillOrKillUnfillable = errors.New("fill-or-kill order cannot be filled completely")
+)
+
+// TimeInForce controls how long a limit order stays on the book
+type TimeInForce string
+
+const (
+	// TimeInForceGTC keeps the order until it is filled or cancelled (default)
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceIOC fills what it can immediately and cancels the rest
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK fills the whole order immediately or not at all
+	TimeInForceFOK TimeInForce = "FOK"
+	// TimeInForcePostOnly only places the order if it adds liquidity
+	TimeInForcePostOnly TimeInForce = "POST_ONLY"
+)
+
+// Valid reports whether tif is a known value. The empty value means GTC.
+func (tif TimeInForce) Valid() bool {
+	switch tif {
+	case "", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK, TimeInForcePostOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// futuresOrderType maps a limit order with a time in force onto the futures type code.
+// The futures API accepts IOC, FOK and POST_ONLY as order types in place of LIMIT.
+func futuresOrderType(orderType OrderType, tif TimeInForce) (OrderType, error) {
+	if !tif.Valid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTimeInForce, tif)
+	}
+	if !strings.EqualFold(string(orderType), OrderTypeLimit) || tif == "" || tif == TimeInForceGTC {
+		return orderType, nil
+	}
+	return OrderType(tif), nil
+}
+
+// Spot orders only support LIMIT and MARKET, so time in force is emulated client-side:
+// post-only and fill-or-kill are checked against the book before placing the order, and
+// the residual of IOC and FOK orders is cancelled right after placement.
+// The depth check and the placement are not atomic, so a spot FOK order can still end
+// up partially filled when the book moves in between.
+
+// checkSpotTimeInForce runs the pre-trade checks of a spot limit order
+func (e *ExchangeAPI) checkSpotTimeInForce(req CreateOrderRequest) error {
+	buy := req.Side.String() == OrderSideBuy
+
+	switch req.TimeInForce {
+	case "", TimeInForceGTC, TimeInForceIOC:
+		return nil
+
+	case TimeInForcePostOnly:
+		ticker, err := e.GetTicker(req.Symbol)
+		if err != nil {
+			return fmt.Errorf("failed to check post-only order: %w", err)
+		}
+		if buy && ticker.SellPrice.IsPositive() && req.Price.GreaterThanOrEqual(ticker.SellPrice) ||
+			!buy && ticker.BuyPrice.IsPositive() && req.Price.LessThanOrEqual(ticker.BuyPrice) {
+			return ErrPostOnlyWouldCross
+		}
+		return nil
+
+	case TimeInForceFOK:
+		depth, err := e.GetDepth(req.Symbol, 0)
+		if err != nil {
+			return fmt.Errorf("failed to check fill-or-kill order: %w", err)
+		}
+		levels := depth.Bids
+		if buy {
+			levels = depth.Asks
+		}
+		if fillableAmount(levels, req.Price, buy).LessThan(req.Amount) {
+			return ErrFillOrKillUnfillable
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidTimeInForce, req.TimeInForce)
+	}
+}
+
+// fillableAmount sums the book levels at or better than price for the taker side
+func fillableAmount(levels [][]decimal.Decimal, price decimal.Decimal, buy bool) decimal.Decimal {
+	total := decimal.Zero
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		if buy && level[0].GreaterThan(price) || !buy && level[0].LessThan(price) {
+			break
+		}
+		total = total.Add(level[1])
+	}
+	return total
+}
+
+// cancelSpotResidual cancels what is left of an IOC or FOK order after placement.
+// A cancel rejected because the order already completed is not an error.
+func (e *ExchangeAPI) cancelSpotResidual(symbol, orderID string, tif TimeInForce) error {
+	cancelErr := e.CancelOrder(symbol, orderID)
+	if cancelErr == nil {
+		return nil
+	}
+
+	order, err := e.GetOrderInfo(symbol, orderID)
+	if err == nil && isFinalOrderStatus(normalizeOrderStatus(string(order.Status))) {
+		return nil
+	}
+
+	return fmt.Errorf("failed to cancel %s residual of order %s: %w", tif, orderID, cancelErr)
+}
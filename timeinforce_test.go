@@ -0,0 +1,144 @@
+package byex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFuturesAPI_CreateOrder_TimeInForce(t *testing.T) {
+	var types []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["TimeInForce"]; ok {
+			t.Errorf("TimeInForce should not be sent as its own field: %v", body)
+		}
+		types = append(types, body["type"].(string))
+		writeMockResponse(w, `{"orderId":"1"}`)
+	})
+
+	tests := []struct {
+		orderType OrderType
+		tif       TimeInForce
+		want      string
+	}{
+		{OrderTypeLimit, "", "LIMIT"},
+		{OrderTypeLimit, TimeInForceGTC, "LIMIT"},
+		{OrderTypeLimit, TimeInForceIOC, "IOC"},
+		{OrderTypeLimit, TimeInForceFOK, "FOK"},
+		{OrderTypeLimit, TimeInForcePostOnly, "POST_ONLY"},
+		{OrderTypeMarket, TimeInForceIOC, "MARKET"},
+	}
+
+	for i, tt := range tests {
+		_, err := client.Futures().CreateOrder(FuturesCreateOrderRequest{
+			FuturesName: "E-BTC-USDT",
+			Type:        tt.orderType,
+			Volume:      decimal.NewFromInt(1),
+			TimeInForce: tt.tif,
+		})
+		if err != nil {
+			t.Fatalf("CreateOrder() returned error: %v", err)
+		}
+		if types[i] != tt.want {
+			t.Errorf("%s/%s: expected type %s, got %s", tt.orderType, tt.tif, tt.want, types[i])
+		}
+	}
+
+	_, err := client.Futures().CreateOrder(FuturesCreateOrderRequest{Type: OrderTypeLimit, TimeInForce: "GTD"})
+	if !errors.Is(err, ErrInvalidTimeInForce) {
+		t.Errorf("Expected ErrInvalidTimeInForce, got %v", err)
+	}
+}
+
+func TestExchangeAPI_CreateOrder_PostOnly(t *testing.T) {
+	var placed int
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/get_ticker":
+			writeMockResponse(w, `{"symbol":"BTCUSDT","buy":"99","sell":"101"}`)
+		case "/open/api/create_order":
+			placed++
+			writeMockResponse(w, `{"orderId":"1"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	order := CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Amount: decimal.NewFromInt(1), TimeInForce: TimeInForcePostOnly}
+
+	order.Price = decimal.NewFromInt(101)
+	if _, err := client.Exchange().CreateOrder(order); !errors.Is(err, ErrPostOnlyWouldCross) {
+		t.Errorf("Expected ErrPostOnlyWouldCross for a buy at the ask, got %v", err)
+	}
+
+	order.Price = decimal.NewFromInt(100)
+	if _, err := client.Exchange().CreateOrder(order); err != nil {
+		t.Errorf("CreateOrder() returned error: %v", err)
+	}
+
+	order.Side = OrderSideSell
+	order.Price = decimal.NewFromInt(99)
+	if _, err := client.Exchange().CreateOrder(order); !errors.Is(err, ErrPostOnlyWouldCross) {
+		t.Errorf("Expected ErrPostOnlyWouldCross for a sell at the bid, got %v", err)
+	}
+
+	if placed != 1 {
+		t.Errorf("Expected 1 order placed, got %d", placed)
+	}
+}
+
+func TestExchangeAPI_CreateOrder_ImmediateOrCancel(t *testing.T) {
+	var cancelled []string
+	filled := false
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/market_dept":
+			writeMockResponse(w, `{"asks":[["100","1"],["101","2"],["105","10"]],"bids":[]}`)
+		case "/open/api/create_order":
+			writeMockResponse(w, `{"orderId":"9"}`)
+		case "/open/api/cancel_order":
+			r.ParseForm()
+			cancelled = append(cancelled, r.PostForm.Get("order_id"))
+			if filled {
+				w.Write([]byte(`{"code":"22","msg":"order does not exist"}`))
+				return
+			}
+			writeMockResponse(w, `null`)
+		case "/open/api/order_info":
+			writeMockResponse(w, `{"id":"9","status":"FILLED"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	order := CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromInt(101), Amount: decimal.NewFromInt(5)}
+
+	order.TimeInForce = TimeInForceIOC
+	if _, err := client.Exchange().CreateOrder(order); err != nil {
+		t.Fatalf("CreateOrder() returned error: %v", err)
+	}
+	if len(cancelled) != 1 || cancelled[0] != "9" {
+		t.Errorf("Expected IOC residual to be cancelled, got %v", cancelled)
+	}
+
+	// Only 3 are available at or below 101
+	order.TimeInForce = TimeInForceFOK
+	if _, err := client.Exchange().CreateOrder(order); !errors.Is(err, ErrFillOrKillUnfillable) {
+		t.Errorf("Expected ErrFillOrKillUnfillable, got %v", err)
+	}
+
+	// A cancel rejected because the order already filled is not an error
+	filled = true
+	order.Amount = decimal.NewFromInt(3)
+	if _, err := client.Exchange().CreateOrder(order); err != nil {
+		t.Errorf("CreateOrder() of a filled FOK order returned error: %v", err)
+	}
+	if len(cancelled) != 2 {
+		t.Errorf("Expected 2 cancel attempts, got %d", len(cancelled))
+	}
+}
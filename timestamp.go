@@ -0,0 +1,115 @@
+package byex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp is an epoch time as returned by the API, in milliseconds except for the
+// endpoints that send seconds (see Time). It decodes from JSON numbers and from strings
+// holding a number, and encodes back in the form it was decoded from.
+type Timestamp struct {
+	value int64
+	// quoted is set when the API sent the value as a string
+	quoted bool
+}
+
+// timestampLayout is RFC3339 with fixed millisecond precision
+const timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// secondsThreshold separates epoch seconds from milliseconds: 1e11 ms is in 1973,
+// while 1e11 s is thousands of years ahead
+const secondsThreshold = 1e11
+
+// NewTimestamp creates a Timestamp holding the epoch value v, encoded as a number
+func NewTimestamp(v int64) Timestamp {
+	return Timestamp{value: v}
+}
+
+// TimestampFromTime converts t to a millisecond Timestamp. The zero time converts to 0.
+func TimestampFromTime(t time.Time) Timestamp {
+	if t.IsZero() {
+		return Timestamp{}
+	}
+	return Timestamp{value: t.UnixMilli()}
+}
+
+// Int64 returns the epoch value as received, without the seconds detection of Time
+func (ts Timestamp) Int64() int64 {
+	return ts.value
+}
+
+// Time converts ts to a time.Time. 0 converts to the zero time. Values between -1e11
+// and 1e11 are read as seconds rather than milliseconds: some endpoints, such as the
+// klines of a few markets, send seconds, and as milliseconds such values would all
+// fall in 1966-1973. Use Int64 for the value as received.
+func (ts Timestamp) Time() time.Time {
+	switch {
+	case ts.value == 0:
+		return time.Time{}
+	case ts.value < secondsThreshold && ts.value > -secondsThreshold:
+		return time.Unix(ts.value, 0)
+	default:
+		return time.UnixMilli(ts.value)
+	}
+}
+
+// IsZero reports whether ts is unset
+func (ts Timestamp) IsZero() bool {
+	return ts.value == 0
+}
+
+// Equal reports whether ts and other hold the same value, whatever form they were decoded from
+func (ts Timestamp) Equal(other Timestamp) bool {
+	return ts.value == other.value
+}
+
+// String formats ts as RFC3339 with milliseconds in UTC, or an empty string when it is unset
+func (ts Timestamp) String() string {
+	if ts.value == 0 {
+		return ""
+	}
+	return ts.Time().UTC().Format(timestampLayout)
+}
+
+// UnmarshalJSON accepts a number or a string holding a number. null and "" decode as 0.
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalStringOrNumber(data)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	quoted := len(data) > 0 && data[0] == '"'
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		*ts = Timestamp{quoted: quoted}
+		return nil
+	}
+
+	if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		*ts = Timestamp{value: v, quoted: quoted}
+		return nil
+	}
+
+	// Some endpoints send 1.6409952e+12 or a fractional value
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", raw)
+	}
+	*ts = Timestamp{value: int64(v), quoted: quoted}
+	return nil
+}
+
+// MarshalJSON encodes ts as a string when it was decoded from one, and as a number otherwise
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	if ts.quoted {
+		if ts.value == 0 {
+			return []byte(`""`), nil
+		}
+		return json.Marshal(strconv.FormatInt(ts.value, 10))
+	}
+	return json.Marshal(ts.value)
+}
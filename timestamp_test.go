@@ -0,0 +1,75 @@
+package byex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{`1640995200000`, 1640995200000},
+		{`"1640995200000"`, 1640995200000},
+		{`1.6409952e+12`, 1640995200000},
+		{`""`, 0},
+		{`null`, 0},
+	}
+
+	for _, tt := range tests {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(tt.input), &ts); err != nil {
+			t.Errorf("%s: unexpected error %v", tt.input, err)
+			continue
+		}
+		if ts.Int64() != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.input, tt.want, ts.Int64())
+		}
+	}
+
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Error("Expected an error for a non-numeric timestamp")
+	}
+}
+
+func TestTimestamp_Time(t *testing.T) {
+	var order ExchangeOrder
+	if err := json.Unmarshal([]byte(`{"created_at":"1640995200123","updated_at":1640995300000}`), &order); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if !order.CreatedAt.Time().Equal(time.UnixMilli(1640995200123)) {
+		t.Errorf("Expected created_at as milliseconds, got %v", order.CreatedAt.Time())
+	}
+	if order.CreatedAt.String() != "2022-01-01T00:00:00.123Z" {
+		t.Errorf("Expected RFC3339, got %s", order.CreatedAt)
+	}
+
+	data, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	var decoded map[string]interface{}
+	json.Unmarshal(data, &decoded)
+	if decoded["created_at"] != "1640995200123" {
+		t.Errorf("Expected created_at to marshal back as a string, got %v", decoded["created_at"])
+	}
+	if decoded["updated_at"] != float64(1640995300000) {
+		t.Errorf("Expected updated_at to marshal back as a number, got %v", decoded["updated_at"])
+	}
+	if !order.CreatedAt.Equal(NewTimestamp(1640995200123)) {
+		t.Error("Expected timestamps decoded from a string and a number to be equal")
+	}
+
+	if !NewTimestamp(0).Time().IsZero() || !NewTimestamp(1640995200).Time().Equal(time.Unix(1640995200, 0)) {
+		t.Error("Expected 0 as the zero time and small values as seconds")
+	}
+	if NewTimestamp(1640995200).Int64() != 1640995200 {
+		t.Error("Expected Int64 to return seconds as received")
+	}
+	if TimestampFromTime(time.UnixMilli(1640995200123)).Int64() != 1640995200123 || !TimestampFromTime(time.Time{}).IsZero() {
+		t.Error("Expected TimestampFromTime to round-trip milliseconds")
+	}
+}
@@ -0,0 +1,591 @@
+package byex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrTriggerNotFound = errors.New("trigger not found")
+	ErrInvalidTrigger  = errors.New("invalid trigger")
+)
+
+// TriggerKind is the condition type of a trigger
+type TriggerKind string
+
+const (
+	// TriggerStopLoss fires when the price moves against the order side:
+	// at or below StopPrice for sell orders, at or above for buy orders
+	TriggerStopLoss TriggerKind = "STOP_LOSS"
+	// TriggerTakeProfit fires when the price moves in favour of the order side:
+	// at or above StopPrice for sell orders, at or below for buy orders
+	TriggerTakeProfit TriggerKind = "TAKE_PROFIT"
+)
+
+// Trigger statuses
+const (
+	TriggerStatusPending = "PENDING"
+	// TriggerStatusFiring marks a trigger whose order is being placed
+	TriggerStatusFiring    = "FIRING"
+	TriggerStatusFired     = "FIRED"
+	TriggerStatusCancelled = "CANCELLED"
+	TriggerStatusFailed    = "FAILED"
+)
+
+// Trigger is a client-side conditional spot order
+type Trigger struct {
+	ID        string             `json:"id"`
+	Kind      TriggerKind        `json:"kind"`
+	StopPrice decimal.Decimal    `json:"stopPrice"`
+	Order     CreateOrderRequest `json:"order"`
+
+	// OCOGroup links triggers that cancel each other once one of them fires
+	OCOGroup string `json:"ocoGroup,omitempty"`
+
+	Status    string    `json:"status"`
+	OrderID   string    `json:"orderId,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	FiredAt   time.Time `json:"firedAt,omitempty"`
+}
+
+// Triggered reports whether price meets the trigger condition
+func (t Trigger) Triggered(price decimal.Decimal) bool {
+	sell := t.Order.Side.String() == OrderSideSell
+	switch t.Kind {
+	case TriggerStopLoss:
+		if sell {
+			return price.LessThanOrEqual(t.StopPrice)
+		}
+		return price.GreaterThanOrEqual(t.StopPrice)
+	case TriggerTakeProfit:
+		if sell {
+			return price.GreaterThanOrEqual(t.StopPrice)
+		}
+		return price.LessThanOrEqual(t.StopPrice)
+	default:
+		return false
+	}
+}
+
+// TriggerStore persists pending triggers across restarts
+type TriggerStore interface {
+	Load() ([]Trigger, error)
+	Save(triggers []Trigger) error
+}
+
+// FileTriggerStore stores pending triggers as a JSON file
+type FileTriggerStore struct {
+	Path string
+}
+
+// NewFileTriggerStore creates a trigger store at path
+func NewFileTriggerStore(path string) *FileTriggerStore {
+	return &FileTriggerStore{Path: path}
+}
+
+// Load reads the store. A missing file is an empty store.
+func (s *FileTriggerStore) Load() ([]Trigger, error) {
+	var triggers []Trigger
+	if err := readJSONFile(s.Path, &triggers); err != nil {
+		return nil, fmt.Errorf("failed to load triggers: %w", err)
+	}
+
+	return triggers, nil
+}
+
+// Save replaces the store atomically
+func (s *FileTriggerStore) Save(triggers []Trigger) error {
+	if err := writeJSONFile(s.Path, triggers); err != nil {
+		return fmt.Errorf("failed to save triggers: %w", err)
+	}
+
+	return nil
+}
+
+// PriceSource provides the last price of a spot symbol
+type PriceSource interface {
+	LastPrice(symbol string) (decimal.Decimal, error)
+}
+
+// TickerPriceSource reads prices from the spot ticker endpoint
+type TickerPriceSource struct {
+	exchange *ExchangeAPI
+}
+
+// NewTickerPriceSource creates a price source backed by GetTicker
+func NewTickerPriceSource(exchange *ExchangeAPI) *TickerPriceSource {
+	return &TickerPriceSource{exchange: exchange}
+}
+
+// LastPrice returns the last traded price of symbol
+func (s *TickerPriceSource) LastPrice(symbol string) (decimal.Decimal, error) {
+	ticker, err := s.exchange.GetTicker(symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return ticker.Last, nil
+}
+
+// TriggerEngine watches prices and places spot orders when trigger conditions are met.
+// Prices come from Poll, which queries the PriceSource, or from OnPrice for stream users.
+type TriggerEngine struct {
+	exchange *ExchangeAPI
+	prices   PriceSource
+	store    TriggerStore
+	ids      *ClientOrderIDGenerator
+
+	mu       sync.Mutex
+	triggers map[string]*Trigger
+	inFlight map[string]bool // triggers whose order request is in progress
+	onFire   []func(Trigger)
+}
+
+// NewTriggerEngine creates a trigger engine and restores the active triggers of store.
+// Triggers that were firing when the process stopped are resolved by the first Poll.
+// store may be nil to keep triggers in memory only.
+func NewTriggerEngine(exchange *ExchangeAPI, store TriggerStore) (*TriggerEngine, error) {
+	e := &TriggerEngine{
+		exchange: exchange,
+		prices:   NewTickerPriceSource(exchange),
+		store:    store,
+		ids:      NewClientOrderIDGenerator("trg", ""),
+		triggers: make(map[string]*Trigger),
+		inFlight: make(map[string]bool),
+	}
+
+	if store != nil {
+		triggers, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range triggers {
+			if !isActiveTrigger(t.Status) {
+				continue
+			}
+			t := t
+			e.triggers[t.ID] = &t
+		}
+	}
+
+	return e, nil
+}
+
+// SetPriceSource replaces the price source used by Poll
+func (e *TriggerEngine) SetPriceSource(source PriceSource) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.prices = source
+}
+
+// OnFire registers a callback invoked after a trigger fires or its order is rejected
+func (e *TriggerEngine) OnFire(fn func(Trigger)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onFire = append(e.onFire, fn)
+}
+
+// Add registers a pending trigger and returns it with its assigned ID
+func (e *TriggerEngine) Add(t Trigger) (Trigger, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.add(&t); err != nil {
+		return Trigger{}, err
+	}
+	if err := e.save(); err != nil {
+		delete(e.triggers, t.ID)
+		return Trigger{}, err
+	}
+
+	return t, nil
+}
+
+// AddOCO registers a stop-loss and a take-profit trigger as a one-cancels-the-other pair
+func (e *TriggerEngine) AddOCO(stopLoss, takeProfit Trigger) (Trigger, Trigger, error) {
+	stopLoss.Kind = TriggerStopLoss
+	takeProfit.Kind = TriggerTakeProfit
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	group := e.ids.Next()
+	stopLoss.OCOGroup = group
+	takeProfit.OCOGroup = group
+
+	if err := e.add(&stopLoss); err != nil {
+		return Trigger{}, Trigger{}, err
+	}
+	if err := e.add(&takeProfit); err != nil {
+		delete(e.triggers, stopLoss.ID)
+		return Trigger{}, Trigger{}, err
+	}
+	if err := e.save(); err != nil {
+		delete(e.triggers, stopLoss.ID)
+		delete(e.triggers, takeProfit.ID)
+		return Trigger{}, Trigger{}, err
+	}
+
+	return stopLoss, takeProfit, nil
+}
+
+// add validates t and stores it. The caller must hold e.mu.
+func (e *TriggerEngine) add(t *Trigger) error {
+	if t.Order.Symbol == "" || t.Order.Side == "" || t.Order.Type == "" {
+		return fmt.Errorf("%w: order symbol, side and type are required", ErrInvalidTrigger)
+	}
+	if err := validateOrder(t.Order.Side, t.Order.Type); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTrigger, err)
+	}
+	if t.Kind != TriggerStopLoss && t.Kind != TriggerTakeProfit {
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidTrigger, t.Kind)
+	}
+	if !t.StopPrice.IsPositive() {
+		return fmt.Errorf("%w: stop price must be positive", ErrInvalidTrigger)
+	}
+
+	if t.ID == "" {
+		t.ID = e.ids.Next()
+	}
+	if _, ok := e.triggers[t.ID]; ok {
+		return fmt.Errorf("%w: duplicate id %s", ErrInvalidTrigger, t.ID)
+	}
+
+	// A fixed client order ID lets a restart find an order sent just before a crash
+	if t.Order.ClientOrderID == "" {
+		t.Order.ClientOrderID = t.ID
+	}
+	if err := validateClientOrderID(t.Order.ClientOrderID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+	t.Status = TriggerStatusPending
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+
+	stored := *t
+	e.triggers[t.ID] = &stored
+	return nil
+}
+
+// Cancel cancels a pending or failed trigger together with the other pending legs of its OCO group
+func (e *TriggerEngine) Cancel(id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, ok := e.triggers[id]
+	if !ok || t.Status != TriggerStatusPending && t.Status != TriggerStatusFailed {
+		return ErrTriggerNotFound
+	}
+	t.Status = TriggerStatusCancelled
+	if t.OCOGroup != "" {
+		for _, other := range e.triggers {
+			if other.Status == TriggerStatusPending && other.OCOGroup == t.OCOGroup {
+				other.Status = TriggerStatusCancelled
+			}
+		}
+	}
+
+	return e.save()
+}
+
+// Trigger returns a snapshot of a trigger
+func (e *TriggerEngine) Trigger(id string) (Trigger, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, ok := e.triggers[id]
+	if !ok {
+		return Trigger{}, false
+	}
+	return *t, true
+}
+
+// Pending returns the pending triggers ordered by creation time
+func (e *TriggerEngine) Pending() []Trigger {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var result []Trigger
+	for _, t := range e.sortedPending("") {
+		result = append(result, *t)
+	}
+	return result
+}
+
+// OnPrice evaluates the pending triggers of symbol against price and fires those whose condition is met
+func (e *TriggerEngine) OnPrice(symbol string, price decimal.Decimal) error {
+	e.mu.Lock()
+	busy := e.busyGroups()
+	var firing []*Trigger
+	for _, t := range e.sortedPending(symbol) {
+		if !t.Triggered(price) || t.OCOGroup != "" && busy[t.OCOGroup] {
+			continue
+		}
+		if t.OCOGroup != "" {
+			busy[t.OCOGroup] = true
+		}
+		t.Status = TriggerStatusFiring
+		t.FiredAt = time.Now()
+		e.inFlight[t.ID] = true
+		firing = append(firing, t)
+	}
+
+	if len(firing) == 0 {
+		e.mu.Unlock()
+		return nil
+	}
+
+	// Persist the in-flight state before any order is sent, so a restart
+	// can look the order up by its client order ID instead of losing it
+	if err := e.save(); err != nil {
+		for _, t := range firing {
+			t.Status = TriggerStatusPending
+			delete(e.inFlight, t.ID)
+		}
+		e.mu.Unlock()
+		return err
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	for _, t := range firing {
+		if err := e.fire(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// busyGroups returns the OCO groups with a leg in flight or already fired. The caller must hold e.mu.
+func (e *TriggerEngine) busyGroups() map[string]bool {
+	busy := make(map[string]bool)
+	for _, t := range e.triggers {
+		if t.OCOGroup != "" && (t.Status == TriggerStatusFiring || t.Status == TriggerStatusFired) {
+			busy[t.OCOGroup] = true
+		}
+	}
+	return busy
+}
+
+// fire places the order of a firing trigger.
+//
+// On success the other legs of its OCO group are cancelled. If the order is rejected,
+// by the exchange or by a local check such as a RiskGuard, the trigger fails and its
+// siblings stay pending, so the position keeps its other protection. Only if the request
+// may have reached the exchange without an answer (e.g. a network error) does the
+// trigger stay FIRING, to be resolved by the next Poll.
+func (e *TriggerEngine) fire(t *Trigger) error {
+	e.mu.Lock()
+	req := t.Order
+	e.mu.Unlock()
+
+	resp, err := e.exchange.CreateOrder(req)
+
+	var saveErr error
+	switch {
+	case resp != nil:
+		// Placed, possibly with a follow-up error such as a failed IOC residual cancel
+		saveErr = e.complete(t, resp.OrderID, err)
+	case isOutcomeUnknown(err):
+		e.mu.Lock()
+		delete(e.inFlight, t.ID)
+		e.mu.Unlock()
+	default:
+		saveErr = e.complete(t, "", err)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to fire trigger %s: %w", t.ID, err)
+	}
+	return errors.Join(err, saveErr)
+}
+
+// complete records and persists the outcome of a firing trigger and notifies the
+// OnFire callbacks. An empty orderID marks the trigger failed.
+func (e *TriggerEngine) complete(t *Trigger, orderID string, orderErr error) error {
+	e.mu.Lock()
+	delete(e.inFlight, t.ID)
+	t.Error = ""
+	if orderErr != nil {
+		t.Error = orderErr.Error()
+	}
+
+	if orderID == "" {
+		t.Status = TriggerStatusFailed
+	} else {
+		t.Status = TriggerStatusFired
+		t.OrderID = orderID
+		if t.OCOGroup != "" {
+			for _, other := range e.triggers {
+				if other.ID != t.ID && other.OCOGroup == t.OCOGroup && other.Status == TriggerStatusPending {
+					other.Status = TriggerStatusCancelled
+				}
+			}
+		}
+	}
+
+	snapshot := *t
+	saveErr := e.save()
+	callbacks := append([]func(Trigger){}, e.onFire...)
+	e.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(snapshot)
+	}
+
+	return saveErr
+}
+
+// resolveFiring settles triggers left FIRING by a crash or a network error.
+// The order is looked up by its client order ID: if it exists the trigger is
+// marked fired, and only if the exchange reports it as not found does the trigger
+// fire again. Any other lookup error leaves it FIRING for the next Poll.
+func (e *TriggerEngine) resolveFiring() error {
+	e.mu.Lock()
+	var firing []*Trigger
+	for _, t := range e.triggers {
+		if t.Status == TriggerStatusFiring && !e.inFlight[t.ID] {
+			e.inFlight[t.ID] = true
+			firing = append(firing, t)
+		}
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	for _, t := range firing {
+		order, err := e.exchange.GetOrderInfoByClientOrderID(t.Order.Symbol, t.Order.ClientOrderID)
+		switch {
+		case err == nil && order.ID != "":
+			if err := e.complete(t, order.ID, nil); err != nil {
+				errs = append(errs, err)
+			}
+		case isOrderNotFound(err):
+			// Never reached the exchange; a duplicate would be rejected by its client order ID
+			if err := e.fire(t); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			if err == nil {
+				err = errors.New("order lookup returned no order")
+			}
+			e.mu.Lock()
+			delete(e.inFlight, t.ID)
+			e.mu.Unlock()
+			errs = append(errs, fmt.Errorf("failed to resolve trigger %s: %w", t.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Retry re-arms a failed trigger
+func (e *TriggerEngine) Retry(id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, ok := e.triggers[id]
+	if !ok || t.Status != TriggerStatusFailed {
+		return ErrTriggerNotFound
+	}
+	t.Status = TriggerStatusPending
+	t.Error = ""
+
+	return e.save()
+}
+
+// Poll resolves in-flight triggers, then fetches the price of every symbol with
+// pending triggers and evaluates them
+func (e *TriggerEngine) Poll() error {
+	var errs []error
+	if err := e.resolveFiring(); err != nil {
+		errs = append(errs, err)
+	}
+
+	e.mu.Lock()
+	prices := e.prices
+	symbols := newStringSet()
+	for _, t := range e.triggers {
+		if t.Status == TriggerStatusPending {
+			symbols.add(t.Order.Symbol)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, symbol := range symbols.sorted() {
+		price, err := prices.LastPrice(symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get price for %s: %w", symbol, err))
+			continue
+		}
+		if err := e.OnPrice(symbol, price); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run polls prices at interval until ctx is done. Poll errors are passed to onError.
+func (e *TriggerEngine) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := e.Poll(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// sortedPending returns the pending triggers of symbol, or of all symbols if symbol is empty,
+// in creation order. The caller must hold e.mu.
+func (e *TriggerEngine) sortedPending(symbol string) []*Trigger {
+	var result []*Trigger
+	for _, t := range e.triggers {
+		if t.Status == TriggerStatusPending && (symbol == "" || t.Order.Symbol == symbol) {
+			result = append(result, t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// save persists the pending, in-flight and failed triggers. The caller must hold e.mu.
+func (e *TriggerEngine) save() error {
+	if e.store == nil {
+		return nil
+	}
+
+	active := make([]Trigger, 0, len(e.triggers))
+	for _, t := range e.triggers {
+		if isActiveTrigger(t.Status) {
+			active = append(active, *t)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].ID < active[j].ID })
+
+	return e.store.Save(active)
+}
+
+// isActiveTrigger reports whether a trigger with status still needs attention
+func isActiveTrigger(status string) bool {
+	return status == TriggerStatusPending || status == TriggerStatusFiring || status == TriggerStatusFailed
+}
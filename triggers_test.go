@@ -0,0 +1,373 @@
+package byex
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+type staticPriceSource map[string]decimal.Decimal
+
+func (s staticPriceSource) LastPrice(symbol string) (decimal.Decimal, error) {
+	return s[symbol], nil
+}
+
+func TestTrigger_Triggered(t *testing.T) {
+	stop := decimal.NewFromInt(100)
+	tests := []struct {
+		kind  TriggerKind
+		side  OrderSide
+		price int64
+		want  bool
+	}{
+		{TriggerStopLoss, OrderSideSell, 99, true},
+		{TriggerStopLoss, OrderSideSell, 101, false},
+		{TriggerStopLoss, OrderSideBuy, 101, true},
+		{TriggerStopLoss, OrderSideBuy, 99, false},
+		{TriggerTakeProfit, OrderSideSell, 100, true},
+		{TriggerTakeProfit, OrderSideSell, 99, false},
+		{TriggerTakeProfit, OrderSideBuy, 99, true},
+		{TriggerTakeProfit, OrderSideBuy, 101, false},
+	}
+
+	for _, tt := range tests {
+		trigger := Trigger{Kind: tt.kind, StopPrice: stop, Order: CreateOrderRequest{Side: tt.side}}
+		if got := trigger.Triggered(decimal.NewFromInt(tt.price)); got != tt.want {
+			t.Errorf("%s %s at %d: expected %v, got %v", tt.kind, tt.side, tt.price, tt.want, got)
+		}
+	}
+}
+
+func TestTriggerEngine_OCO(t *testing.T) {
+	var placed []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/get_ticker":
+			writeMockResponse(w, `{"symbol":"BTCUSDT","last":"89"}`)
+		case "/open/api/create_order":
+			r.ParseForm()
+			placed = append(placed, r.PostForm.Get("side")+" "+r.PostForm.Get("type"))
+			writeMockResponse(w, `{"orderId":"42"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	store := NewFileTriggerStore(filepath.Join(t.TempDir(), "triggers.json"))
+	engine, err := NewTriggerEngine(client.Exchange(), store)
+	if err != nil {
+		t.Fatalf("NewTriggerEngine() returned error: %v", err)
+	}
+
+	order := CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}
+	stopLoss, takeProfit, err := engine.AddOCO(
+		Trigger{StopPrice: decimal.NewFromInt(90), Order: order},
+		Trigger{StopPrice: decimal.NewFromInt(120), Order: order},
+	)
+	if err != nil {
+		t.Fatalf("AddOCO() returned error: %v", err)
+	}
+
+	// Pending triggers survive a restart
+	restored, err := NewTriggerEngine(client.Exchange(), store)
+	if err != nil {
+		t.Fatalf("NewTriggerEngine() returned error: %v", err)
+	}
+	if len(restored.Pending()) != 2 {
+		t.Fatalf("Expected 2 restored triggers, got %d", len(restored.Pending()))
+	}
+
+	var fired []Trigger
+	restored.OnFire(func(tr Trigger) { fired = append(fired, tr) })
+
+	if err := restored.Poll(); err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+
+	if len(placed) != 1 || placed[0] != "SELL MARKET" {
+		t.Errorf("Expected one market sell, got %v", placed)
+	}
+	if len(fired) != 1 || fired[0].ID != stopLoss.ID || fired[0].OrderID != "42" {
+		t.Errorf("Expected stop loss to fire with order 42, got %+v", fired)
+	}
+	if tp, _ := restored.Trigger(takeProfit.ID); tp.Status != TriggerStatusCancelled {
+		t.Errorf("Expected take profit to be cancelled, got %s", tp.Status)
+	}
+
+	// A second poll must not fire again
+	if err := restored.Poll(); err != nil || len(placed) != 1 {
+		t.Errorf("Expected no further orders, got %v (err %v)", placed, err)
+	}
+
+	saved, err := store.Load()
+	if err != nil || len(saved) != 0 {
+		t.Errorf("Expected empty store after firing, got %d (err %v)", len(saved), err)
+	}
+}
+
+func TestTriggerEngine_OnPrice(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeMockResponse(w, `{"orderId":"7"}`)
+	})
+
+	engine, _ := NewTriggerEngine(client.Exchange(), nil)
+	engine.SetPriceSource(staticPriceSource{"ETHUSDT": decimal.NewFromInt(3000)})
+
+	if _, err := engine.Add(Trigger{Kind: TriggerStopLoss, StopPrice: decimal.NewFromInt(10)}); err == nil {
+		t.Error("Expected error for trigger without order")
+	}
+
+	buy, err := engine.Add(Trigger{
+		Kind:      TriggerStopLoss,
+		StopPrice: decimal.NewFromInt(3100),
+		Order:     CreateOrderRequest{Symbol: "ETHUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)},
+	})
+	if err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+
+	if err := engine.Poll(); err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+	if tr, _ := engine.Trigger(buy.ID); tr.Status != TriggerStatusPending {
+		t.Errorf("Expected trigger to stay pending at 3000, got %s", tr.Status)
+	}
+
+	if err := engine.OnPrice("ETHUSDT", decimal.NewFromInt(3150)); err != nil {
+		t.Fatalf("OnPrice() returned error: %v", err)
+	}
+	if tr, _ := engine.Trigger(buy.ID); tr.Status != TriggerStatusFired || tr.OrderID != "7" {
+		t.Errorf("Expected trigger to fire with order 7, got %+v", tr)
+	}
+
+	if err := engine.Cancel(buy.ID); err != ErrTriggerNotFound {
+		t.Errorf("Expected ErrTriggerNotFound for fired trigger, got %v", err)
+	}
+}
+
+func TestTriggerEngine_RejectedLegKeepsSibling(t *testing.T) {
+	reject := true
+	var clientIDs []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		clientIDs = append(clientIDs, r.PostForm.Get("client_order_id"))
+		if reject {
+			w.Write([]byte(`{"code":"10003","msg":"insufficient balance"}`))
+			return
+		}
+		writeMockResponse(w, `{"orderId":"5"}`)
+	})
+
+	store := NewFileTriggerStore(filepath.Join(t.TempDir(), "triggers.json"))
+	engine, _ := NewTriggerEngine(client.Exchange(), store)
+
+	order := CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}
+	stopLoss, takeProfit, err := engine.AddOCO(
+		Trigger{StopPrice: decimal.NewFromInt(90), Order: order},
+		Trigger{StopPrice: decimal.NewFromInt(120), Order: order},
+	)
+	if err != nil {
+		t.Fatalf("AddOCO() returned error: %v", err)
+	}
+
+	if err := engine.OnPrice("BTCUSDT", decimal.NewFromInt(85)); err == nil {
+		t.Error("Expected OnPrice() to report the rejected order")
+	}
+	if clientIDs[0] != stopLoss.ID {
+		t.Errorf("Expected the trigger ID as client order ID, got %q", clientIDs[0])
+	}
+
+	sl, _ := engine.Trigger(stopLoss.ID)
+	tp, _ := engine.Trigger(takeProfit.ID)
+	if sl.Status != TriggerStatusFailed || sl.Error == "" {
+		t.Errorf("Expected failed stop loss with error, got %+v", sl)
+	}
+	if tp.Status != TriggerStatusPending {
+		t.Errorf("Take profit should stay pending when the stop loss is rejected, got %s", tp.Status)
+	}
+
+	saved, _ := store.Load()
+	if len(saved) != 2 {
+		t.Errorf("Expected failed and pending triggers to be persisted, got %+v", saved)
+	}
+
+	reject = false
+	if err := engine.Retry(stopLoss.ID); err != nil {
+		t.Fatalf("Retry() returned error: %v", err)
+	}
+	if err := engine.OnPrice("BTCUSDT", decimal.NewFromInt(85)); err != nil {
+		t.Fatalf("OnPrice() returned error: %v", err)
+	}
+	if tp, _ := engine.Trigger(takeProfit.ID); tp.Status != TriggerStatusCancelled {
+		t.Errorf("Expected take profit to be cancelled after the retry fired, got %s", tp.Status)
+	}
+}
+
+func TestTriggerEngine_ResolvesFiringAfterRestart(t *testing.T) {
+	var created []string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/order_info":
+			if r.URL.Query().Get("client_order_id") == "placed" {
+				writeMockResponse(w, `{"id":"77","client_order_id":"placed","status":"FILLED"}`)
+				return
+			}
+			w.Write([]byte(`{"code":"22","msg":"order does not exist"}`))
+		case "/open/api/create_order":
+			r.ParseForm()
+			created = append(created, r.PostForm.Get("client_order_id"))
+			writeMockResponse(w, `{"orderId":"78"}`)
+		case "/open/api/get_ticker":
+			writeMockResponse(w, `{"last":"100"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	order := CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}
+	withID := func(id string) CreateOrderRequest {
+		o := order
+		o.ClientOrderID = id
+		return o
+	}
+
+	// State left behind by a crash while two orders were being sent
+	store := NewFileTriggerStore(filepath.Join(t.TempDir(), "triggers.json"))
+	store.Save([]Trigger{
+		{ID: "a", Kind: TriggerStopLoss, StopPrice: decimal.NewFromInt(90), Order: withID("placed"), OCOGroup: "g1", Status: TriggerStatusFiring},
+		{ID: "b", Kind: TriggerTakeProfit, StopPrice: decimal.NewFromInt(120), Order: withID("b"), OCOGroup: "g1", Status: TriggerStatusPending},
+		{ID: "c", Kind: TriggerStopLoss, StopPrice: decimal.NewFromInt(90), Order: withID("lost"), Status: TriggerStatusFiring},
+	})
+
+	engine, err := NewTriggerEngine(client.Exchange(), store)
+	if err != nil {
+		t.Fatalf("NewTriggerEngine() returned error: %v", err)
+	}
+	if err := engine.Poll(); err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+
+	if a, _ := engine.Trigger("a"); a.Status != TriggerStatusFired || a.OrderID != "77" {
+		t.Errorf("Expected trigger a to be confirmed from the exchange, got %+v", a)
+	}
+	if b, _ := engine.Trigger("b"); b.Status != TriggerStatusCancelled {
+		t.Errorf("Expected OCO sibling b to be cancelled, got %s", b.Status)
+	}
+	if c, _ := engine.Trigger("c"); c.Status != TriggerStatusFired || c.OrderID != "78" {
+		t.Errorf("Expected trigger c to be fired again, got %+v", c)
+	}
+	if len(created) != 1 || created[0] != "lost" {
+		t.Errorf("Expected only the lost order to be re-sent with its client order ID, got %v", created)
+	}
+}
+
+func TestTriggerEngine_LocalRejectionFails(t *testing.T) {
+	requests := 0
+	guard := NewRiskGuard(RiskLimits{})
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code":"22","msg":"order does not exist"}`))
+	}, ClientOption{Testnet: true, RiskGuard: guard})
+	guard.Kill()
+
+	engine, _ := NewTriggerEngine(client.Exchange(), nil)
+	engine.SetPriceSource(staticPriceSource{"BTCUSDT": decimal.NewFromInt(85)})
+	var fired []Trigger
+	engine.OnFire(func(t Trigger) { fired = append(fired, t) })
+
+	trigger, err := engine.Add(Trigger{Kind: TriggerStopLoss, StopPrice: decimal.NewFromInt(90), Order: CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}})
+	if err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+
+	if err := engine.Poll(); !errors.Is(err, ErrKillSwitch) {
+		t.Errorf("Expected ErrKillSwitch, got %v", err)
+	}
+	if got, _ := engine.Trigger(trigger.ID); got.Status != TriggerStatusFailed {
+		t.Errorf("Expected a locally rejected trigger to fail, got %s", got.Status)
+	}
+	if len(fired) != 1 || fired[0].Status != TriggerStatusFailed {
+		t.Errorf("Expected OnFire to report the failure, got %+v", fired)
+	}
+
+	// A failed trigger is neither looked up nor fired again
+	if err := engine.Poll(); err != nil {
+		t.Errorf("Poll() returned error: %v", err)
+	}
+	if requests != 0 || len(fired) != 1 {
+		t.Errorf("Expected no requests and no more callbacks, got %d requests and %d callbacks", requests, len(fired))
+	}
+}
+
+func TestTriggerEngine_FiringOnlyRefiresWhenNotFound(t *testing.T) {
+	created := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open/api/order_info":
+			w.Write([]byte(`{"code":"10002","msg":"invalid api key"}`))
+		case "/open/api/create_order":
+			created++
+			writeMockResponse(w, `{"orderId":"78"}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	store := NewFileTriggerStore(filepath.Join(t.TempDir(), "triggers.json"))
+	store.Save([]Trigger{{ID: "a", Kind: TriggerStopLoss, StopPrice: decimal.NewFromInt(90), Status: TriggerStatusFiring,
+		Order: CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1), ClientOrderID: "a"}}})
+
+	engine, err := NewTriggerEngine(client.Exchange(), store)
+	if err != nil {
+		t.Fatalf("NewTriggerEngine() returned error: %v", err)
+	}
+	if err := engine.Poll(); err == nil {
+		t.Error("Expected the failed lookup to be reported")
+	}
+	if a, _ := engine.Trigger("a"); a.Status != TriggerStatusFiring || created != 0 {
+		t.Errorf("Expected the trigger to stay FIRING without a new order, got %s and %d orders", a.Status, created)
+	}
+}
+
+func TestTriggerEngine_UnknownOutcomeStaysFiring(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// A gateway error page: the order may or may not have been placed
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`<html>502 Bad Gateway</html>`))
+	})
+
+	engine, _ := NewTriggerEngine(client.Exchange(), nil)
+	trigger, _ := engine.Add(Trigger{Kind: TriggerStopLoss, StopPrice: decimal.NewFromInt(90), Order: CreateOrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}})
+
+	if err := engine.OnPrice("BTCUSDT", decimal.NewFromInt(85)); err == nil {
+		t.Error("Expected the unreadable response to be reported")
+	}
+	if got, _ := engine.Trigger(trigger.ID); got.Status != TriggerStatusFiring {
+		t.Errorf("Expected the trigger to stay FIRING until it is looked up, got %s", got.Status)
+	}
+}
+
+func TestTriggerEngine_AddValidatesOrder(t *testing.T) {
+	engine, _ := NewTriggerEngine(NewClient(testApiKey, testSecretKey).Exchange(), nil)
+
+	_, err := engine.Add(Trigger{Kind: TriggerStopLoss, StopPrice: decimal.NewFromInt(90), Order: CreateOrderRequest{Symbol: "BTCUSDT", Side: "HOLD", Type: OrderTypeMarket}})
+	if !errors.Is(err, ErrInvalidTrigger) || !errors.Is(err, ErrInvalidOrderSide) {
+		t.Errorf("Expected ErrInvalidTrigger and ErrInvalidOrderSide, got %v", err)
+	}
+}
+
+func TestFileTriggerStore_KeepsTimeInForce(t *testing.T) {
+	store := NewFileTriggerStore(filepath.Join(t.TempDir(), "triggers.json"))
+	store.Save([]Trigger{{ID: "a", Status: TriggerStatusPending, Order: CreateOrderRequest{Symbol: "BTCUSDT", TimeInForce: TimeInForcePostOnly}}})
+
+	triggers, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].Order.TimeInForce != TimeInForcePostOnly {
+		t.Errorf("Expected post-only time in force to survive a restart, got %+v", triggers)
+	}
+}
@@ -8,17 +8,20 @@ import (
 
 // ExchangeOrder represents an order in the exchange
 type ExchangeOrder struct {
+	rawFields
+
 	ID               string          `json:"id"`
+	ClientOrderID    string          `json:"client_order_id"`
 	Symbol           string          `json:"symbol"`
-	Type             string          `json:"type"`
-	Side             string          `json:"side"`
+	Type             OrderType       `json:"type"`
+	Side             OrderSide       `json:"side"`
 	Amount           decimal.Decimal `json:"amount"`
 	Price            decimal.Decimal `json:"price"`
-	Status           string          `json:"status"`
-	CreatedAt        int64           `json:"created_at"`
-	UpdatedAt        int64           `json:"updated_at"`
-	FinishedAt       int64           `json:"finished_at"`
-	CancelledAt      int64           `json:"cancelled_at"`
+	Status           OrderStatus     `json:"status"`
+	CreatedAt        Timestamp       `json:"created_at"`
+	UpdatedAt        Timestamp       `json:"updated_at"`
+	FinishedAt       Timestamp       `json:"finished_at"`
+	CancelledAt      Timestamp       `json:"cancelled_at"`
 	AvgPrice         decimal.Decimal `json:"avg_price"`
 	Source           string          `json:"source"`
 	Fee              decimal.Decimal `json:"fee"`
@@ -30,20 +33,24 @@ type ExchangeOrder struct {
 
 // ExchangeTrade represents a trade in the exchange
 type ExchangeTrade struct {
+	rawFields
+
 	ID          string          `json:"id"`
 	OrderID     string          `json:"order_id"`
 	Symbol      string          `json:"symbol"`
-	Side        string          `json:"side"`
+	Side        OrderSide       `json:"side"`
 	Amount      decimal.Decimal `json:"amount"`
 	Price       decimal.Decimal `json:"price"`
 	Fee         decimal.Decimal `json:"fee"`
 	FeeCurrency string          `json:"fee_currency"`
 	Role        string          `json:"role"`
-	CreatedAt   int64           `json:"created_at"`
+	CreatedAt   Timestamp       `json:"created_at"`
 }
 
 // ExchangeTicker represents ticker information
 type ExchangeTicker struct {
+	rawFields
+
 	Symbol      string          `json:"symbol"`
 	High        decimal.Decimal `json:"high"`
 	Low         decimal.Decimal `json:"low"`
@@ -59,13 +66,17 @@ type ExchangeTicker struct {
 
 // ExchangeDepth represents order book depth
 type ExchangeDepth struct {
+	rawFields
+
 	Asks [][]decimal.Decimal `json:"asks"`
 	Bids [][]decimal.Decimal `json:"bids"`
 }
 
 // ExchangeKline represents candlestick data
 type ExchangeKline struct {
-	Time   int64           `json:"time"`
+	rawFields
+
+	Time   Timestamp       `json:"time"`
 	Open   decimal.Decimal `json:"open"`
 	High   decimal.Decimal `json:"high"`
 	Low    decimal.Decimal `json:"low"`
@@ -75,6 +86,8 @@ type ExchangeKline struct {
 
 // ExchangeAccount represents user account information
 type ExchangeAccount struct {
+	rawFields
+
 	TotalAsset    decimal.Decimal `json:"total_asset"`
 	CoinList      []CoinBalance   `json:"coin_list"`
 	NormalCount   decimal.Decimal `json:"normal_count"`
@@ -86,6 +99,8 @@ type ExchangeAccount struct {
 
 // CoinBalance represents balance for a specific coin
 type CoinBalance struct {
+	rawFields
+
 	Coin     string          `json:"coin"`
 	Normal   decimal.Decimal `json:"normal"`
 	Locked   decimal.Decimal `json:"locked"`
@@ -97,34 +112,42 @@ type CoinBalance struct {
 
 // FuturesOrder represents a futures order
 type FuturesOrder struct {
+	rawFields
+
 	OrderID       string          `json:"orderId"`
 	ClientOrderID string          `json:"clientOrderId"`
 	Symbol        string          `json:"symbol"`
-	Type          string          `json:"type"`
-	Side          string          `json:"side"`
+	Type          OrderType       `json:"type"`
+	Side          OrderSide       `json:"side"`
 	Open          string          `json:"open"`
-	PositionType  string          `json:"positionType"`
+	PositionType  PositionType    `json:"positionType"`
 	Price         decimal.Decimal `json:"price"`
 	Volume        decimal.Decimal `json:"volume"`
-	Status        string          `json:"status"`
-	CreatedAt     int64           `json:"created_at"`
-	UpdatedAt     int64           `json:"updated_at"`
+	ExecutedQty   decimal.Decimal `json:"executedQty"`
+	AvgPrice      decimal.Decimal `json:"avgPrice"`
+	Status        OrderStatus     `json:"status"`
+	CreatedAt     Timestamp       `json:"created_at"`
+	UpdatedAt     Timestamp       `json:"updated_at"`
 }
 
 // FuturesTrade represents a futures trade
 type FuturesTrade struct {
+	rawFields
+
 	ID        string          `json:"id"`
 	OrderID   string          `json:"order_id"`
 	Symbol    string          `json:"symbol"`
-	Side      string          `json:"side"`
+	Side      OrderSide       `json:"side"`
 	Volume    decimal.Decimal `json:"volume"`
 	Price     decimal.Decimal `json:"price"`
 	Fee       decimal.Decimal `json:"fee"`
-	Timestamp int64           `json:"timestamp"`
+	Timestamp Timestamp       `json:"timestamp"`
 }
 
 // FuturesPosition represents a futures position
 type FuturesPosition struct {
+	rawFields
+
 	Symbol            string          `json:"symbol"`
 	PositionSide      string          `json:"positionSide"`
 	PositionAmt       decimal.Decimal `json:"positionAmt"`
@@ -140,6 +163,8 @@ type FuturesPosition struct {
 
 // FuturesAccount represents futures account information
 type FuturesAccount struct {
+	rawFields
+
 	AccountId       string          `json:"accountId"`
 	CollateralCoin  string          `json:"collateralCoin"`
 	AccountBalance  decimal.Decimal `json:"accountBalance"`
@@ -150,6 +175,8 @@ type FuturesAccount struct {
 
 // FuturesTicker represents futures ticker information
 type FuturesTicker struct {
+	rawFields
+
 	Symbol             string          `json:"symbol"`
 	PriceChange        decimal.Decimal `json:"priceChange"`
 	PriceChangePercent decimal.Decimal `json:"priceChangePercent"`
@@ -161,8 +188,8 @@ type FuturesTicker struct {
 	LowPrice           decimal.Decimal `json:"lowPrice"`
 	Volume             decimal.Decimal `json:"volume"`
 	QuoteVolume        decimal.Decimal `json:"quoteVolume"`
-	OpenTime           int64           `json:"openTime"`
-	CloseTime          int64           `json:"closeTime"`
+	OpenTime           Timestamp       `json:"openTime"`
+	CloseTime          Timestamp       `json:"closeTime"`
 	Count              int64           `json:"count"`
 }
 
@@ -171,25 +198,89 @@ type FuturesTicker struct {
 // CreateOrderRequest represents a create order request
 type CreateOrderRequest struct {
 	Symbol        string          `json:"symbol"`
-	Side          string          `json:"side"`
-	Type          string          `json:"type"`
+	Side          OrderSide       `json:"side"`
+	Type          OrderType       `json:"type"`
 	Amount        decimal.Decimal `json:"amount,omitempty"`
 	Price         decimal.Decimal `json:"price,omitempty"`
 	ClientOrderID string          `json:"client_order_id,omitempty"`
+
+	// TimeInForce applies to limit orders placed with CreateOrder. The spot API has no
+	// native support, so it is emulated client-side. It is never sent to the exchange;
+	// the JSON tag only keeps it when requests are persisted (e.g. by a TriggerStore).
+	TimeInForce TimeInForce `json:"timeInForce,omitempty"`
 }
 
 // FuturesCreateOrderRequest represents a futures create order request
 type FuturesCreateOrderRequest struct {
 	FuturesName   string          `json:"futuresName"`
-	Type          string          `json:"type"`
-	Side          string          `json:"side"`
+	Type          OrderType       `json:"type"`
+	Side          OrderSide       `json:"side"`
+	Open          string          `json:"open"`
+	PositionType  PositionType    `json:"positionType"`
+	Price         decimal.Decimal `json:"price,omitempty"`
+	Volume        decimal.Decimal `json:"volume"`
+	ClientOrderID string          `json:"clientOrderId,omitempty"`
+
+	// TimeInForce applies to limit orders and is sent as the futures order type
+	TimeInForce TimeInForce `json:"-"`
+}
+
+// FuturesConditionOrderRequest represents a futures conditional (trigger) order request.
+// The order described by Type, Price and Volume is placed once the TriggerType price
+// reaches TriggerPrice.
+type FuturesConditionOrderRequest struct {
+	FuturesName   string          `json:"futuresName"`
+	Type          OrderType       `json:"type"`
+	Side          OrderSide       `json:"side"`
 	Open          string          `json:"open"`
-	PositionType  string          `json:"positionType"`
+	PositionType  PositionType    `json:"positionType"`
 	Price         decimal.Decimal `json:"price,omitempty"`
 	Volume        decimal.Decimal `json:"volume"`
+	TriggerPrice  decimal.Decimal `json:"triggerPrice"`
+	TriggerType   string          `json:"triggerType,omitempty"`
 	ClientOrderID string          `json:"clientOrderId,omitempty"`
 }
 
+// FuturesPositionTPSLRequest attaches take-profit and stop-loss orders to an open position.
+// Zero prices leave that side unset, and a zero Volume covers the whole position.
+type FuturesPositionTPSLRequest struct {
+	FuturesName     string          `json:"futuresName"`
+	Side            OrderSide       `json:"side"`
+	PositionType    PositionType    `json:"positionType"`
+	Volume          decimal.Decimal `json:"volume,omitempty"`
+	TakeProfitPrice decimal.Decimal `json:"takeProfitPrice,omitempty"`
+	StopLossPrice   decimal.Decimal `json:"stopLossPrice,omitempty"`
+	TriggerType     string          `json:"triggerType,omitempty"`
+}
+
+// FuturesPositionTPSLResponse represents the orders created for a position take-profit/stop-loss
+type FuturesPositionTPSLResponse struct {
+	rawFields
+
+	TakeProfitOrderID string `json:"takeProfitOrderId"`
+	StopLossOrderID   string `json:"stopLossOrderId"`
+}
+
+// FuturesConditionOrder represents a pending futures conditional order
+type FuturesConditionOrder struct {
+	rawFields
+
+	OrderID       string          `json:"orderId"`
+	ClientOrderID string          `json:"clientOrderId"`
+	Symbol        string          `json:"symbol"`
+	Type          OrderType       `json:"type"`
+	Side          OrderSide       `json:"side"`
+	Open          string          `json:"open"`
+	PositionType  PositionType    `json:"positionType"`
+	Price         decimal.Decimal `json:"price"`
+	Volume        decimal.Decimal `json:"volume"`
+	TriggerPrice  decimal.Decimal `json:"triggerPrice"`
+	TriggerType   string          `json:"triggerType"`
+	Status        OrderStatus     `json:"status"`
+	CreatedAt     Timestamp       `json:"created_at"`
+	UpdatedAt     Timestamp       `json:"updated_at"`
+}
+
 // BatchOrderRequest represents a batch order request
 type BatchOrderRequest struct {
 	Symbol string               `json:"symbol"`
@@ -200,28 +291,38 @@ type BatchOrderRequest struct {
 
 // OrderResponse represents order creation response
 type OrderResponse struct {
-	OrderID string `json:"orderId"`
+	rawFields
+
+	OrderID       string `json:"orderId"`
+	ClientOrderID string `json:"clientOrderId,omitempty"`
 }
 
 // OrderListResponse represents order list response
 type OrderListResponse struct {
+	rawFields
+
 	Count      int             `json:"count"`
 	ResultList []ExchangeOrder `json:"resultList"`
 }
 
 // TradeListResponse represents trade list response
 type TradeListResponse struct {
+	rawFields
+
 	Count      int             `json:"count"`
 	ResultList []ExchangeTrade `json:"resultList"`
 }
 
 // TickerListResponse represents ticker list response
 type TickerListResponse struct {
-	Date   int64            `json:"date"`
+	rawFields
+
+	Date   Timestamp        `json:"date"`
 	Ticker []ExchangeTicker `json:"ticker"`
 }
 
-// Constants for order types, sides, etc.
+// Constants for order types, sides, etc. They are untyped so they can be used both with
+// the typed fields (OrderSide, OrderType, ...) and with plain strings.
 const (
 	// Order Types
 	OrderTypeLimit  = "LIMIT"
@@ -245,6 +346,11 @@ const (
 	// Futures Trade Types
 	FuturesTradeTypeOpen  = "OPEN"
 	FuturesTradeTypeClose = "CLOSE"
+
+	// Futures Trigger Price Types
+	FuturesTriggerTypeLast  = "LAST"
+	FuturesTriggerTypeMark  = "MARK"
+	FuturesTriggerTypeIndex = "INDEX"
 )
 
 // Additional Exchange Types
@@ -253,14 +359,16 @@ const (
 type BatchOrder struct {
 	Volume        decimal.Decimal `json:"volume"`
 	Price         decimal.Decimal `json:"price,omitempty"`
-	Side          string          `json:"side"`
-	Type          int             `json:"type"`       // 1: limit, 2: market
-	VolumeType    int             `json:"volumeType"` // 1: amount value, 2: base coin amount
+	Side          OrderSide       `json:"side"`
+	Type          int             `json:"type"`       // 1: limit, 2: market, see OrderTypeFromCode
+	VolumeType    VolumeType      `json:"volumeType"` // VolumeTypeQuote or VolumeTypeBase
 	ClientOrderID string          `json:"clientOrderId,omitempty"`
 }
 
 // BatchOrderResponse represents the response from batch order operations
 type BatchOrderResponse struct {
+	rawFields
+
 	Success []BatchOrderResult `json:"success"`
 	Failed  []BatchOrderResult `json:"failed"`
 }
@@ -275,6 +383,7 @@ type BatchOrderResult struct {
 
 // ExchangeOrderDetail represents detailed order information
 type ExchangeOrderDetail struct {
+	rawFields
 	ExchangeOrder
 	Trades []ExchangeTrade `json:"trades"`
 }
@@ -283,8 +392,8 @@ type ExchangeOrderDetail struct {
 type ReplaceOrderRequest struct {
 	Symbol        string          `json:"symbol"`
 	CancelOrderID string          `json:"cancel_order"`
-	Side          string          `json:"side"`
-	Type          string          `json:"type"`
+	Side          OrderSide       `json:"side"`
+	Type          OrderType       `json:"type"`
 	Amount        decimal.Decimal `json:"amount,omitempty"`
 	Price         decimal.Decimal `json:"price,omitempty"`
 	ClientOrderID string          `json:"client_order_id,omitempty"`
@@ -292,6 +401,8 @@ type ReplaceOrderRequest struct {
 
 // SymbolCharge represents symbol with charge information
 type SymbolCharge struct {
+	rawFields
+
 	Symbol              string          `json:"symbol"`
 	BaseAsset           string          `json:"baseAsset"`
 	QuoteAsset          string          `json:"quoteAsset"`
@@ -310,6 +421,8 @@ type SymbolCharge struct {
 
 // LeverageFinanceBalance represents leverage finance balance information
 type LeverageFinanceBalance struct {
+	rawFields
+
 	Symbol        string          `json:"symbol"`
 	BaseAsset     string          `json:"baseAsset"`
 	QuoteAsset    string          `json:"quoteAsset"`
@@ -328,6 +441,7 @@ type LeverageFinanceBalance struct {
 
 // FuturesOrderDetail represents detailed futures order information
 type FuturesOrderDetail struct {
+	rawFields
 	FuturesOrder
 	Trades []FuturesTrade `json:"trades"`
 }
@@ -340,13 +454,30 @@ type FuturesBatchOrderRequest struct {
 
 // FuturesIndexPrice represents index price information
 type FuturesIndexPrice struct {
+	rawFields
+
 	Symbol     string          `json:"symbol"`
 	IndexPrice decimal.Decimal `json:"indexPrice"`
-	Time       int64           `json:"time"`
+	MarkPrice  decimal.Decimal `json:"markPrice"`
+	Time       Timestamp       `json:"time"`
+}
+
+// FuturesContract represents the contract metadata of a futures symbol
+type FuturesContract struct {
+	Symbol         string          `json:"symbol"`
+	ContractName   string          `json:"contractName"`
+	Status         int             `json:"status"`
+	Multiplier     decimal.Decimal `json:"multiplier"`
+	MultiplierCoin string          `json:"multiplierCoin"`
+	PricePrecision int             `json:"pricePrecision"`
+	MinOrderVolume decimal.Decimal `json:"minOrderVolume"`
+	MaxLimitVolume decimal.Decimal `json:"maxLimitVolume"`
 }
 
 // FuturesCapital represents capital/fund information
 type FuturesCapital struct {
+	rawFields
+
 	Asset                  string          `json:"asset"`
 	WalletBalance          decimal.Decimal `json:"walletBalance"`
 	UnrealizedPnl          decimal.Decimal `json:"unrealizedPnl"`
@@ -17,10 +17,10 @@ func TestExchangeOrder(t *testing.T) {
 		Amount:           decimal.NewFromFloat(0.01),
 		Price:            decimal.NewFromFloat(45000),
 		Status:           "filled",
-		CreatedAt:        1640995200000,
-		UpdatedAt:        1640995300000,
-		FinishedAt:       1640995400000,
-		CancelledAt:      0,
+		CreatedAt:        NewTimestamp(1640995200000),
+		UpdatedAt:        NewTimestamp(1640995300000),
+		FinishedAt:       NewTimestamp(1640995400000),
+		CancelledAt:      NewTimestamp(0),
 		AvgPrice:         decimal.NewFromFloat(45100),
 		Source:           "api",
 		Fee:              decimal.NewFromFloat(0.1),
@@ -63,7 +63,7 @@ func TestExchangeTrade(t *testing.T) {
 		Fee:         decimal.NewFromFloat(0.05),
 		FeeCurrency: "USDT",
 		Role:        "taker",
-		CreatedAt:   1640995200000,
+		CreatedAt:   NewTimestamp(1640995200000),
 	}
 
 	jsonData, err := json.Marshal(trade)
@@ -150,7 +150,7 @@ func TestExchangeDepth(t *testing.T) {
 // Test ExchangeKline type
 func TestExchangeKline(t *testing.T) {
 	kline := ExchangeKline{
-		Time:   1640995200000,
+		Time:   NewTimestamp(1640995200000),
 		Open:   decimal.NewFromFloat(47000),
 		High:   decimal.NewFromFloat(48000),
 		Low:    decimal.NewFromFloat(46500),
@@ -170,7 +170,7 @@ func TestExchangeKline(t *testing.T) {
 	}
 
 	if unmarshaledKline.Time != kline.Time {
-		t.Errorf("Expected Time %d, got %d", kline.Time, unmarshaledKline.Time)
+		t.Errorf("Expected Time %d, got %d", kline.Time.Int64(), unmarshaledKline.Time.Int64())
 	}
 }
 
@@ -256,8 +256,8 @@ func TestFuturesOrder(t *testing.T) {
 		Price:         decimal.NewFromFloat(45000),
 		Volume:        decimal.NewFromFloat(0.01),
 		Status:        "NEW",
-		CreatedAt:     1640995200000,
-		UpdatedAt:     1640995300000,
+		CreatedAt:     NewTimestamp(1640995200000),
+		UpdatedAt:     NewTimestamp(1640995300000),
 	}
 
 	jsonData, err := json.Marshal(order)
@@ -386,8 +386,9 @@ func TestBatchOrder(t *testing.T) {
 		t.Errorf("Failed to unmarshal BatchOrder: %v", err)
 	}
 
-	if unmarshaledOrder.Side != batchOrder.Side {
-		t.Errorf("Expected Side %s, got %s", batchOrder.Side, unmarshaledOrder.Side)
+	// Sides are sent in their canonical spelling
+	if unmarshaledOrder.Side != OrderSideBuy {
+		t.Errorf("Expected Side %s, got %s", OrderSideBuy, unmarshaledOrder.Side)
 	}
 
 	if unmarshaledOrder.Type != batchOrder.Type {
@@ -632,7 +633,7 @@ func TestRequestValidation(t *testing.T) {
 func TestResponseTypes(t *testing.T) {
 	// Test TickerListResponse
 	tickerList := TickerListResponse{
-		Date: 1640995200,
+		Date: NewTimestamp(1640995200),
 		Ticker: []ExchangeTicker{
 			{
 				Symbol: "BTCUSDT",
@@ -715,8 +716,8 @@ func TestFuturesTypes(t *testing.T) {
 		LowPrice:           decimal.NewFromFloat(46000),
 		Volume:             decimal.NewFromFloat(1000000),
 		QuoteVolume:        decimal.NewFromFloat(47500000000),
-		OpenTime:           1640995200000,
-		CloseTime:          1641081600000,
+		OpenTime:           NewTimestamp(1640995200000),
+		CloseTime:          NewTimestamp(1641081600000),
 		Count:              50000,
 	}
 
@@ -728,7 +729,7 @@ func TestFuturesTypes(t *testing.T) {
 	indexPrice := FuturesIndexPrice{
 		Symbol:     "BTCUSDT",
 		IndexPrice: decimal.NewFromFloat(47800),
-		Time:       1640995200000,
+		Time:       NewTimestamp(1640995200000),
 	}
 
 	if indexPrice.Symbol == "" {